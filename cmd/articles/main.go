@@ -56,9 +56,7 @@ func main() {
 
 	// repo
 	articlesRepo := repository.NewArticlesRepository(dbpool)
-
-	// service
-	articleService := service.NewArticlesService(log, articlesRepo, articlesCache)
+	commentsRepo := repository.NewCommentsRepository(dbpool)
 
 	// grpc client
 	grpcAuthClient, err := grpcclient.NewAuthClient(context.Background(),
@@ -72,8 +70,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	// service
+	articleService := service.NewArticlesService(log, articlesRepo, articlesCache, grpcAuthClient, grpcAuthClient)
+	commentsService := service.NewCommentsService(log, commentsRepo, articlesRepo, grpcAuthClient, grpcAuthClient)
+	feedService := service.NewFeedService(log, articlesRepo, articlesCache, service.FeedConfig{
+		BaseURL:     cfg.Feed.BaseURL,
+		Title:       cfg.Feed.Title,
+		Description: cfg.Feed.Description,
+		Limit:       cfg.Feed.Limit,
+	})
+
+	// scheduler
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+
+	publishScheduler := service.NewPublishScheduler(log,
+		articlesRepo,
+		articlesCache,
+		cfg.Scheduler.Interval,
+		cfg.Scheduler.BatchSize,
+	)
+	go publishScheduler.Run(schedulerCtx)
+
+	var tokenValidator httphandler.TokenValidator = grpcAuthClient
+	if cfg.Auth.LocalVerification {
+		tokenValidator = grpcclient.NewVerifier(grpcAuthClient, log, grpcclient.VerifierConfig{
+			RevocationCheck:     cfg.Auth.RevocationCheck,
+			CacheTTL:            cfg.Auth.CacheTTL,
+			KeysRefreshInterval: cfg.Auth.KeysRefreshInterval,
+		})
+	}
+
 	// router
-	handler := httphandler.NewHandler(articleService, log, grpcAuthClient)
+	handler := httphandler.NewHandler(articleService, commentsService, feedService, log, tokenValidator)
 	router := handler.InitRouter()
 
 	srv := &http.Server{
@@ -102,6 +131,7 @@ func main() {
 
 	case <-shutdown:
 		log.Info("Starting graceful shutdown")
+		stopScheduler()
 
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 		defer cancel()
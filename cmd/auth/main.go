@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/config"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/oauth"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/repository"
 	authService "github.com/mSulimenko/dev-blog-platform/internal/auth/service"
 	authGrpc "github.com/mSulimenko/dev-blog-platform/internal/auth/transport/grpc"
@@ -43,6 +44,8 @@ func main() {
 
 	// repo
 	usersRepo := repository.NewUsersRepository(dbpool)
+	tokensRepo := repository.NewTokensRepository(dbpool)
+	throttlesRepo := repository.NewThrottlesRepository(dbpool)
 
 	// Инициализируем Kafka Dispatcher
 	kafkaDispatcher, err := kafka.NewKafkaDispatcher(cfg.Kafka.Brokers, log)
@@ -52,17 +55,80 @@ func main() {
 	}
 	defer kafkaDispatcher.Close()
 
+	// signing keys
+	keySet := authService.NewKeySet(cfg.Auth.AccessSecret)
+	for _, key := range cfg.Auth.SigningKeys {
+		if err = keySet.LoadKey(key.ID, key.Algorithm, key.PrivateKeyPath); err != nil {
+			log.Error("failed to load signing key: ", err)
+			os.Exit(1)
+		}
+	}
+	if err = keySet.SetActive(cfg.Auth.ActiveKeyID); err != nil {
+		log.Error("invalid signing keys configuration: ", err)
+		os.Exit(1)
+	}
+
+	// oauth providers
+	oauthProviders := make(map[string]authService.OAuthProvider)
+	if cfg.Auth.OAuth.GitHub.ClientID != "" {
+		oauthProviders["github"] = oauth.NewGitHubProvider(oauth.GitHubConfig{
+			ClientID:     cfg.Auth.OAuth.GitHub.ClientID,
+			ClientSecret: cfg.Auth.OAuth.GitHub.ClientSecret,
+			RedirectURL:  cfg.Auth.OAuth.GitHub.RedirectURL,
+		})
+	}
+	for _, provider := range cfg.Auth.OAuth.OIDC {
+		oauthProviders[provider.Name] = oauth.NewOIDCProvider(oauth.OIDCConfig{
+			IssuerURL:    provider.IssuerURL,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		})
+	}
+
 	// services
 	userService := authService.NewUsersService(
 		usersRepo,
+		tokensRepo,
+		throttlesRepo,
 		kafkaDispatcher,
 		log,
-		cfg.Auth.AccessSecret,
+		keySet,
 		cfg.Auth.AccessDuration,
+		cfg.Auth.RefreshDuration,
+		cfg.Auth.ResetDuration,
+		cfg.Auth.EmailChangeDuration,
+		cfg.Auth.VerifyDuration,
+		cfg.Auth.VerifyResendInterval,
+		authService.MFAConfig{
+			Issuer:            cfg.Auth.MFA.Issuer,
+			EncryptionKey:     cfg.Auth.MFA.EncryptionKey,
+			ChallengeDuration: cfg.Auth.MFA.ChallengeDuration,
+		},
+		authService.LockoutConfig{
+			Window:         cfg.Auth.Lockout.Window,
+			FreeAttempts:   cfg.Auth.Lockout.FreeAttempts,
+			IPFreeAttempts: cfg.Auth.Lockout.IPFreeAttempts,
+			BaseDelay:      cfg.Auth.Lockout.BaseDelay,
+			MaxDelay:       cfg.Auth.Lockout.MaxDelay,
+			LockThreshold:  cfg.Auth.Lockout.LockThreshold,
+			LockDuration:   cfg.Auth.Lockout.LockDuration,
+		},
+		authService.OAuthConfig{
+			Providers:     oauthProviders,
+			StateDuration: cfg.Auth.OAuth.StateDuration,
+		},
 	)
+	authServ := authService.NewAuthService(usersRepo, tokensRepo, tokensRepo, log, keySet)
 
 	// router
-	handler := httphandler.NewHandler(userService, log)
+	trustedProxies, err := httphandler.ParseTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+
+	handler := httphandler.NewHandler(userService, authServ, keySet, log, trustedProxies)
 	router := handler.InitRouter()
 
 	srv := &http.Server{
@@ -79,8 +145,7 @@ func main() {
 		log.Fatalf("tcp connection failed: %w", err)
 	}
 	gRPCServer := grpc.NewServer()
-	authServ := authService.NewAuthService(usersRepo, log, cfg.Auth.AccessSecret)
-	authGrpc.Register(gRPCServer, authServ)
+	authGrpc.Register(gRPCServer, authServ, keySet)
 	go func() {
 		log.Infof("Starting grpc server on :%s", cfg.GRPC.Port)
 		gRPCServer.Serve(conn)
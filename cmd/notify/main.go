@@ -23,6 +23,7 @@ func main() {
 		SMTPPort:     cfg.Email.SMTPPort,
 		FromEmail:    cfg.Email.FromEmail,
 		FromPassword: cfg.Email.FromPassword,
+		AppURL:       cfg.Email.AppURL,
 	}, log)
 
 	consumer, err := kafka.NewConsumer(cfg.Kafka.Brokers, emailService, log)
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"github.com/go-redis/cache/v9"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/feed"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
 	"github.com/redis/go-redis/v9"
 	"time"
@@ -56,3 +57,41 @@ func (r *RedisCache) SetLatestArticles(ctx context.Context, articles []*models.A
 func (r *RedisCache) InvalidateLatestArticles(ctx context.Context) error {
 	return r.cache.Delete(ctx, "latest_articles:10")
 }
+
+func feedKey(format, authorId string) string {
+	if authorId == "" {
+		return "feed:" + format + ":all"
+	}
+	return "feed:" + format + ":author:" + authorId
+}
+
+func (r *RedisCache) GetFeed(ctx context.Context, format, authorId string) ([]byte, error) {
+	var body []byte
+	err := r.cache.Get(ctx, feedKey(format, authorId), &body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (r *RedisCache) SetFeed(ctx context.Context, format, authorId string, body []byte) error {
+	return r.cache.Set(&cache.Item{
+		Ctx:   ctx,
+		Key:   feedKey(format, authorId),
+		Value: body,
+		TTL:   time.Minute * 15,
+	})
+}
+
+// InvalidateFeeds сбрасывает общие ленты и ленты автора во всех форматах
+func (r *RedisCache) InvalidateFeeds(ctx context.Context, authorId string) error {
+	for _, format := range []string{feed.FormatRSS, feed.FormatAtom} {
+		if err := r.cache.Delete(ctx, feedKey(format, "")); err != nil {
+			return err
+		}
+		if err := r.cache.Delete(ctx, feedKey(format, authorId)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
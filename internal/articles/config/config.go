@@ -12,11 +12,14 @@ const (
 )
 
 type Config struct {
-	Env   string `yaml:"env" env:"ENV" env-default:"local"`
-	HTTP  HTTP   `yaml:"http"`
-	DB    DB     `yaml:"db"`
-	GRPC  GRPC   `yaml:"grpc"`
-	Redis Redis  `yaml:"redis"`
+	Env       string    `yaml:"env" env:"ENV" env-default:"local"`
+	HTTP      HTTP      `yaml:"http"`
+	DB        DB        `yaml:"db"`
+	GRPC      GRPC      `yaml:"grpc"`
+	Redis     Redis     `yaml:"redis"`
+	Scheduler Scheduler `yaml:"scheduler"`
+	Feed      Feed      `yaml:"feed"`
+	Auth      Auth      `yaml:"auth"`
 }
 
 type HTTP struct {
@@ -48,6 +51,29 @@ type Redis struct {
 	DB       int    `yaml:"db" env:"REDIS_DB" env-default:"0"`
 }
 
+type Scheduler struct {
+	Interval  time.Duration `yaml:"interval" env:"SCHEDULER_INTERVAL" env-default:"30s"`
+	BatchSize int           `yaml:"batch_size" env-default:"100"`
+}
+
+type Feed struct {
+	BaseURL     string `yaml:"base_url" env:"FEED_BASE_URL" env-default:"http://localhost:8082"`
+	Title       string `yaml:"title" env-default:"Dev Blog"`
+	Description string `yaml:"description" env-default:"Latest articles from Dev Blog"`
+	Limit       int    `yaml:"limit" env-default:"20"`
+}
+
+// Auth настраивает локальную проверку токенов по публичным ключам auth-сервиса.
+// С RevocationCheck (по умолчанию) токены с верной подписью дополнительно проверяются в auth и
+// успешный результат не кешируется, поэтому выход и завершение сессии действуют сразу.
+// Без него отзыв вступает в силу, только когда истечёт access токен, а для API ключа - через CacheTTL.
+type Auth struct {
+	LocalVerification   bool          `yaml:"local_verification" env:"AUTH_LOCAL_VERIFICATION" env-default:"true"`
+	RevocationCheck     bool          `yaml:"revocation_check" env:"AUTH_REVOCATION_CHECK" env-default:"true"`
+	CacheTTL            time.Duration `yaml:"cache_ttl" env:"AUTH_CACHE_TTL" env-default:"30s"`
+	KeysRefreshInterval time.Duration `yaml:"keys_refresh_interval" env-default:"10m"`
+}
+
 func Load() *Config {
 
 	configPath := os.Getenv("ARTICLES_CONFIG_PATH")
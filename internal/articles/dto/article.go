@@ -6,64 +6,94 @@ import (
 )
 
 type CreateRequest struct {
-	Title    string `json:"title" validate:"required,min=1,max=255"`
-	Content  string `json:"content" validate:"required,min=1"`
-	Status   string `json:"status" validate:"required,oneof=draft published archived"`
-	AuthorId string `json:"author_id" validate:"required"`
+	Title         string     `json:"title" validate:"required,min=1,max=255"`
+	Content       string     `json:"content" validate:"required,min=1,max=100000"`
+	ContentFormat string     `json:"content_format" validate:"omitempty,oneof=markdown html plain"`
+	Status        string     `json:"status" validate:"required,oneof=draft published archived"`
+	AuthorId      string     `json:"author_id" validate:"required"`
+	Tags          []string   `json:"tags" validate:"omitempty,max=10,dive,min=1,max=50"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
 }
 
 type ListRequest struct {
-	AuthorID *string `json:"author_id" validate:"omitempty"`
-	Status   *string `json:"status" validate:"omitempty,oneof=draft published archived"`
-	Offset   int     `json:"offset" validate:"min=0"`
-	Limit    int     `json:"limit" validate:"min=1,max=100"`
+	AuthorID *string  `json:"author_id" validate:"omitempty"`
+	Status   *string  `json:"status" validate:"omitempty,oneof=draft published archived"`
+	Tags     []string `json:"tags" validate:"omitempty,max=10,dive,min=1,max=50"`
+	TagMatch string   `json:"tag_match" validate:"omitempty,oneof=any all"`
+	Cursor   string   `json:"cursor"`
+	Offset   int      `json:"offset" validate:"min=0"`
+	Limit    int      `json:"limit" validate:"min=1,max=100"`
 }
 
 type ListResponse struct {
-	Articles []ArticleResponse `json:"articles"`
-	Total    int               `json:"total"`
-	Offset   int               `json:"offset"`
-	Limit    int               `json:"limit"`
+	Articles   []ArticleResponse `json:"articles"`
+	Total      int               `json:"total"`
+	Offset     int               `json:"offset"`
+	Limit      int               `json:"limit"`
+	HasMore    bool              `json:"has_more"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 type UpdateRequest struct {
-	Title   *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
-	Content *string `json:"content,omitempty" validate:"omitempty,min=1"`
-	Status  *string `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	Title          *string    `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Content        *string    `json:"content,omitempty" validate:"omitempty,min=1,max=100000"`
+	ContentFormat  *string    `json:"content_format,omitempty" validate:"omitempty,oneof=markdown html plain"`
+	Status         *string    `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	Tags           *[]string  `json:"tags,omitempty" validate:"omitempty,max=10,dive,min=1,max=50"`
+	PublishAt      *time.Time `json:"publish_at,omitempty"`
+	ClearPublishAt bool       `json:"clear_publish_at,omitempty"`
 }
 
 type ArticleResponse struct {
-	ID        string    `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	AuthorID  string    `json:"author_id"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            string          `json:"id"`
+	Slug          string          `json:"slug"`
+	Title         string          `json:"title"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHTML   string          `json:"content_html"`
+	AuthorID      string          `json:"author_id"`
+	Author        *AuthorResponse `json:"author,omitempty"`
+	Status        string          `json:"status"`
+	Tags          []string        `json:"tags"`
+	PublishAt     *time.Time      `json:"publish_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
 func FromArticleModel(article *models.Article) ArticleResponse {
+	tags := article.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	return ArticleResponse{
-		ID:        article.Id,
-		Title:     article.Title,
-		Content:   article.Content,
-		AuthorID:  article.AuthorId,
-		Status:    article.Status,
-		CreatedAt: article.CreatedAt,
-		UpdatedAt: article.UpdatedAt,
+		ID:            article.Id,
+		Slug:          article.Slug,
+		Title:         article.Title,
+		Content:       article.Content,
+		ContentFormat: article.ContentFormat,
+		ContentHTML:   article.ContentHTML,
+		AuthorID:      article.AuthorId,
+		Status:        article.Status,
+		Tags:          tags,
+		PublishAt:     article.PublishAt,
+		CreatedAt:     article.CreatedAt,
+		UpdatedAt:     article.UpdatedAt,
 	}
 }
 
-func FromArticleModels(articles []*models.Article, offset, limit int) ListResponse {
+func FromArticleModels(articles []*models.Article, total, offset, limit int, nextCursor string) ListResponse {
 	articleResponses := make([]ArticleResponse, len(articles))
 	for i, article := range articles {
 		articleResponses[i] = FromArticleModel(article)
 	}
 
 	return ListResponse{
-		Articles: articleResponses,
-		Total:    len(articles),
-		Offset:   offset,
-		Limit:    limit,
+		Articles:   articleResponses,
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		HasMore:    nextCursor != "",
+		NextCursor: nextCursor,
 	}
 }
@@ -0,0 +1,18 @@
+package dto
+
+import "github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+
+type AuthorResponse struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+func FromAuthorModel(author *models.Author) *AuthorResponse {
+	if author == nil {
+		return nil
+	}
+	return &AuthorResponse{
+		ID:       author.Id,
+		Username: author.Username,
+	}
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"time"
+)
+
+type CreateCommentRequest struct {
+	Content  string  `json:"content" validate:"required,min=1,max=5000"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type ListCommentsRequest struct {
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
+}
+
+type CommentResponse struct {
+	ID        string            `json:"id"`
+	ArticleID string            `json:"article_id"`
+	ParentID  *string           `json:"parent_id,omitempty"`
+	AuthorID  string            `json:"author_id"`
+	Author    *AuthorResponse   `json:"author,omitempty"`
+	Content   string            `json:"content"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Replies   []CommentResponse `json:"replies,omitempty"`
+}
+
+type CommentsResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func FromCommentModel(comment *models.Comment) CommentResponse {
+	var replies []CommentResponse
+	if len(comment.Replies) > 0 {
+		replies = make([]CommentResponse, len(comment.Replies))
+		for i, reply := range comment.Replies {
+			replies[i] = FromCommentModel(reply)
+		}
+	}
+
+	return CommentResponse{
+		ID:        comment.Id,
+		ArticleID: comment.ArticleId,
+		ParentID:  comment.ParentId,
+		AuthorID:  comment.AuthorId,
+		Content:   comment.Content,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Replies:   replies,
+	}
+}
+
+func FromCommentModels(comments []*models.Comment, nextCursor string) CommentsResponse {
+	commentResponses := make([]CommentResponse, len(comments))
+	for i, comment := range comments {
+		commentResponses[i] = FromCommentModel(comment)
+	}
+
+	return CommentsResponse{
+		Comments:   commentResponses,
+		NextCursor: nextCursor,
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"encoding/base64"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"strings"
+	"time"
+)
+
+// EncodeCursor упаковывает позицию в непрозрачную для клиента строку
+func EncodeCursor(cursor models.Cursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.Id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(encoded string) (*models.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, models.ErrInvalidCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+
+	return &models.Cursor{CreatedAt: t, Id: id}, nil
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"time"
+)
+
+const (
+	DiffOpEqual  = "equal"
+	DiffOpInsert = "insert"
+	DiffOpDelete = "delete"
+)
+
+type RevisionResponse struct {
+	Revision  int       `json:"revision"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content,omitempty"`
+	EditorID  string    `json:"editor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RevisionsResponse struct {
+	ArticleID string             `json:"article_id"`
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type RevisionDiffResponse struct {
+	ArticleID string     `json:"article_id"`
+	From      int        `json:"from"`
+	To        int        `json:"to"`
+	TitleFrom string     `json:"title_from"`
+	TitleTo   string     `json:"title_to"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+func FromRevisionModel(revision *models.Revision) RevisionResponse {
+	return RevisionResponse{
+		Revision:  revision.Revision,
+		Title:     revision.Title,
+		Content:   revision.Content,
+		EditorID:  revision.EditorId,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+func FromRevisionModels(articleId string, revisions []*models.Revision) RevisionsResponse {
+	revisionResponses := make([]RevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		revisionResponses[i] = FromRevisionModel(revision)
+	}
+
+	return RevisionsResponse{
+		ArticleID: articleId,
+		Revisions: revisionResponses,
+	}
+}
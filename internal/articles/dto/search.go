@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+
+type SearchRequest struct {
+	Query  string `json:"q" validate:"required,min=1,max=200"`
+	Offset int    `json:"offset" validate:"min=0"`
+	Limit  int    `json:"limit" validate:"min=1,max=100"`
+}
+
+type SearchResultResponse struct {
+	ArticleResponse
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type SearchResponse struct {
+	Results []SearchResultResponse `json:"results"`
+	Query   string                 `json:"query"`
+	Offset  int                    `json:"offset"`
+	Limit   int                    `json:"limit"`
+}
+
+func FromSearchResults(results []*models.SearchResult, query string, offset, limit int) SearchResponse {
+	resultResponses := make([]SearchResultResponse, len(results))
+	for i, result := range results {
+		resultResponses[i] = SearchResultResponse{
+			ArticleResponse: FromArticleModel(result.Article),
+			Rank:            result.Rank,
+			TitleHighlight:  result.TitleHighlight,
+			Snippet:         result.Snippet,
+		}
+	}
+
+	return SearchResponse{
+		Results: resultResponses,
+		Query:   query,
+		Offset:  offset,
+		Limit:   limit,
+	}
+}
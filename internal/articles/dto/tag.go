@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+
+type TagResponse struct {
+	Name          string `json:"name"`
+	ArticlesCount int    `json:"articles_count"`
+}
+
+type TagsResponse struct {
+	Tags []TagResponse `json:"tags"`
+}
+
+func FromTagModels(tags []*models.Tag) TagsResponse {
+	tagResponses := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		tagResponses[i] = TagResponse{
+			Name:          tag.Name,
+			ArticlesCount: tag.ArticlesCount,
+		}
+	}
+
+	return TagsResponse{
+		Tags: tagResponses,
+	}
+}
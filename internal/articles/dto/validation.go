@@ -4,4 +4,6 @@ type ValidationResp struct {
 	Valid  bool
 	UserId string
 	Role   string
+	// Scopes заданы только при входе по API ключу
+	Scopes []string
 }
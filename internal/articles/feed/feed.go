@@ -0,0 +1,174 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+
+	ContentTypeRSS  = "application/rss+xml; charset=utf-8"
+	ContentTypeAtom = "application/atom+xml; charset=utf-8"
+)
+
+type Meta struct {
+	Title       string
+	Description string
+	Link        string
+	SelfLink    string
+}
+
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Content   string
+	HTML      bool
+	Published time.Time
+	Updated   time.Time
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomPerson  `xml:"author"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// RSS строит ленту RSS 2.0, элементы ожидаются отсортированными от новых к старым
+func RSS(meta Meta, items []Item) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         meta.Title,
+			Link:          meta.Link,
+			Description:   meta.Description,
+			LastBuildDate: lastUpdated(items).Format(time.RFC1123Z),
+		},
+	}
+
+	for _, item := range items {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.Published.Format(time.RFC1123Z),
+			Description: item.Content,
+		})
+	}
+
+	return marshal(feed)
+}
+
+// Atom строит ленту Atom 1.0 (RFC 4287)
+func Atom(meta Meta, items []Item) ([]byte, error) {
+	feed := atomFeed{
+		ID:       meta.SelfLink,
+		Title:    meta.Title,
+		Subtitle: meta.Description,
+		Updated:  lastUpdated(items).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: meta.SelfLink, Rel: "self", Type: "application/atom+xml"},
+			{Href: meta.Link, Rel: "alternate"},
+		},
+	}
+
+	for _, item := range items {
+		contentType := "text"
+		if item.HTML {
+			contentType = "html"
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        "urn:uuid:" + item.ID,
+			Title:     item.Title,
+			Updated:   item.Updated.Format(time.RFC3339),
+			Published: item.Published.Format(time.RFC3339),
+			Links:     []atomLink{{Href: item.Link, Rel: "alternate"}},
+			Author:    atomPerson{Name: item.Author},
+			Content:   atomContent{Type: contentType, Body: item.Content},
+		})
+	}
+
+	return marshal(feed)
+}
+
+func lastUpdated(items []Item) time.Time {
+	var updated time.Time
+	for _, item := range items {
+		if item.Updated.After(updated) {
+			updated = item.Updated
+		}
+	}
+	if updated.IsZero() {
+		return time.Now().UTC()
+	}
+	return updated.UTC()
+}
+
+func marshal(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
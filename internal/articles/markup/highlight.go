@@ -0,0 +1,45 @@
+package markup
+
+import (
+	"html"
+	"strings"
+)
+
+// Маркеры совпадений для ts_headline: символы из области частного использования Unicode,
+// которые переживают экранирование и заменяются на <mark> уже после него
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+// Highlight экранирует текст с маркерами совпадений и превращает маркеры в <mark>.
+// Маркеры в самом тексте статьи дают лишь лишнюю подсветку: теги всегда сбалансированы.
+func Highlight(text string) string {
+	var b strings.Builder
+	open := false
+
+	for {
+		i := strings.IndexAny(text, HighlightStart+HighlightStop)
+		if i < 0 {
+			break
+		}
+		b.WriteString(html.EscapeString(text[:i]))
+
+		marker := text[i : i+len(HighlightStart)]
+		switch {
+		case marker == HighlightStart && !open:
+			b.WriteString("<mark>")
+			open = true
+		case marker == HighlightStop && open:
+			b.WriteString("</mark>")
+			open = false
+		}
+		text = text[i+len(marker):]
+	}
+
+	b.WriteString(html.EscapeString(text))
+	if open {
+		b.WriteString("</mark>")
+	}
+	return b.String()
+}
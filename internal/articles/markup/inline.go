@@ -0,0 +1,344 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	entityRe   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	autolinkRe = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^\s<>]*)>`)
+	emailRe    = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*)>`)
+)
+
+const escapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// renderInline рендерит строчную разметку абзаца или заголовка
+func renderInline(text string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+			continue
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '\n':
+			// Два пробела в конце строки - жёсткий перенос
+			if strings.HasSuffix(b.String(), "  ") {
+				out := strings.TrimRight(b.String(), " ")
+				b.Reset()
+				b.WriteString(out + "<br>\n")
+			} else {
+				b.WriteString("\n")
+			}
+			i++
+			continue
+		case c == '`':
+			if n := renderCodeSpan(&b, text[i:]); n > 0 {
+				i += n
+				continue
+			}
+		case c == '!' && i+1 < len(text) && text[i+1] == '[':
+			if n := renderLink(&b, text[i+1:], true); n > 0 {
+				i += n + 1
+				continue
+			}
+		case c == '[':
+			if n := renderLink(&b, text[i:], false); n > 0 {
+				i += n
+				continue
+			}
+		case c == '<':
+			if n := renderAutolink(&b, text[i:]); n > 0 {
+				i += n
+				continue
+			}
+		case c == '&':
+			if m := entityRe.FindString(text[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m)
+				continue
+			}
+		case c == '*' || c == '_' || c == '~':
+			if n := renderEmphasis(&b, text, i); n > 0 {
+				i += n
+				continue
+			}
+		}
+
+		// Обычный текст, включая нераспознанные служебные символы
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(html.EscapeString(text[i : i+size]))
+		i += size
+	}
+
+	return b.String()
+}
+
+// renderCodeSpan возвращает длину разобранного фрагмента; без закрывающей последовательности кавычки выводятся как текст
+func renderCodeSpan(b *strings.Builder, text string) int {
+	ticks := len(text) - len(strings.TrimLeft(text, "`"))
+	fence := text[:ticks]
+
+	for j := ticks; j < len(text); {
+		k := strings.Index(text[j:], fence)
+		if k < 0 {
+			break
+		}
+		end := j + k
+		// Закрывающая последовательность должна быть той же длины
+		run := len(text[end:]) - len(strings.TrimLeft(text[end:], "`"))
+		if run != ticks {
+			j = end + run
+			continue
+		}
+
+		code := strings.ReplaceAll(text[ticks:end], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+		return end + ticks
+	}
+
+	b.WriteString(html.EscapeString(fence))
+	return ticks
+}
+
+// renderLink разбирает [text](url "title"); для изображений text становится alt
+func renderLink(b *strings.Builder, text string, image bool) int {
+	closeIdx := matchingBracket(text)
+	if closeIdx < 0 || closeIdx+1 >= len(text) || text[closeIdx+1] != '(' {
+		return 0
+	}
+	label := text[1:closeIdx]
+
+	rest := text[closeIdx+2:]
+	dest, title, n, ok := parseLinkTarget(rest)
+	if !ok {
+		return 0
+	}
+
+	if image {
+		b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(plainText(label)) + `"`)
+		if title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(">")
+	} else {
+		b.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+		if title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(">" + renderInline(label) + "</a>")
+	}
+
+	return closeIdx + 2 + n
+}
+
+// matchingBracket находит закрывающую скобку для text[0] == '[' с учётом вложенности и экранирования
+func matchingBracket(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseLinkTarget разбирает `url "title")` и возвращает число прочитанных байт, включая закрывающую скобку
+func parseLinkTarget(text string) (dest, title string, n int, ok bool) {
+	i := skipSpaces(text, 0)
+
+	if i < len(text) && text[i] == '<' {
+		end := strings.IndexAny(text[i+1:], ">\n")
+		if end < 0 || text[i+1+end] != '>' {
+			return "", "", 0, false
+		}
+		dest = text[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start, depth := i, 0
+		for ; i < len(text); i++ {
+			c := text[i]
+			if c == '\\' && i+1 < len(text) {
+				i++
+				continue
+			}
+			if c == ' ' || c == '\n' || c < 0x20 {
+				break
+			}
+			if c == '(' {
+				depth++
+			}
+			if c == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+		dest = unescape(text[start:i])
+	}
+
+	i = skipSpaces(text, i)
+	if i < len(text) && (text[i] == '"' || text[i] == '\'' || text[i] == '(') {
+		closer := text[i]
+		if closer == '(' {
+			closer = ')'
+		}
+		// Экранированная кавычка не закрывает заголовок
+		end := -1
+		for j := i + 1; j < len(text); j++ {
+			if text[j] == '\\' {
+				j++
+				continue
+			}
+			if text[j] == closer {
+				end = j - i - 1
+				break
+			}
+		}
+		if end < 0 {
+			return "", "", 0, false
+		}
+		title = unescape(text[i+1 : i+1+end])
+		i = skipSpaces(text, i+end+2)
+	}
+
+	if i >= len(text) || text[i] != ')' {
+		return "", "", 0, false
+	}
+	return dest, title, i + 1, true
+}
+
+func skipSpaces(text string, i int) int {
+	for i < len(text) && (text[i] == ' ' || text[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return html.UnescapeString(b.String())
+}
+
+// plainText убирает разметку из подписи изображения для атрибута alt
+func plainText(label string) string {
+	return strings.NewReplacer("*", "", "_", "", "`", "", "~", "", "[", "", "]", "").Replace(unescape(label))
+}
+
+func renderAutolink(b *strings.Builder, text string) int {
+	if m := autolinkRe.FindStringSubmatch(text); m != nil {
+		b.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+		return len(m[0])
+	}
+	if m := emailRe.FindStringSubmatch(text); m != nil {
+		b.WriteString(`<a href="mailto:` + html.EscapeString(m[1]) + `">` + html.EscapeString(m[1]) + "</a>")
+		return len(m[0])
+	}
+	return 0
+}
+
+// renderEmphasis обрабатывает *em*, **strong**, _em_, __strong__ и ~~del~~.
+// Это упрощение алгоритма разделителей CommonMark: ищется ближайший подходящий закрывающий разделитель.
+func renderEmphasis(b *strings.Builder, text string, i int) int {
+	c := text[i]
+	run := 0
+	for i+run < len(text) && text[i+run] == c {
+		run++
+	}
+
+	size := 1
+	if run >= 2 {
+		size = 2
+	}
+	if c == '~' && size != 2 {
+		return 0
+	}
+
+	delim := text[i : i+size]
+	start := i + size
+	if start >= len(text) || isSpace(text[start]) {
+		return 0
+	}
+	// Подчёркивание внутри слова не считается выделением
+	if c == '_' && i > 0 && isWordChar(text, i-1) {
+		return 0
+	}
+
+	for j := start + 1; j <= len(text)-size; j++ {
+		if text[j] == '\\' {
+			j++
+			continue
+		}
+		if text[j] == '`' {
+			// Разделители внутри кода не закрывают выделение
+			if end := strings.IndexByte(text[j+1:], '`'); end >= 0 {
+				j += end + 1
+			}
+			continue
+		}
+		if text[j:j+size] != delim || isSpace(text[j-1]) {
+			continue
+		}
+		// Одиночный разделитель не должен закрываться половинкой двойного
+		if size == 1 && j+1 < len(text) && text[j+1] == c {
+			j++
+			continue
+		}
+		if c == '_' && j+size < len(text) && isWordChar(text, j+size) {
+			continue
+		}
+
+		tag := "em"
+		switch {
+		case c == '~':
+			tag = "del"
+		case size == 2:
+			tag = "strong"
+		}
+		b.WriteString("<" + tag + ">" + renderInline(text[start:j]) + "</" + tag + ">")
+		return j + size - i
+	}
+
+	return 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
+
+func isWordChar(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if r == utf8.RuneError {
+		r, _ = utf8.DecodeLastRuneInString(text[:i+1])
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,295 @@
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingRe    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	ruleRe       = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fenceRe      = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \\t]*([^`\\s]*)")
+	listMarkerRe = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])(?:[ \t]+|$)`)
+	setextRe     = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+)
+
+// Markdown преобразует Markdown в HTML. Поддерживается практичное подмножество CommonMark:
+// заголовки, абзацы, списки, цитаты, блоки кода, разделители, ссылки, изображения и выделение.
+// Сырой HTML в исходнике экранируется; результат всё равно нужно пропускать через Sanitize.
+func Markdown(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"), false)
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderBlocks рендерит последовательность блоков; в tight-режиме (плотные списки) абзацы не оборачиваются в <p>
+func renderBlocks(b *strings.Builder, lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fenceRe.MatchString(line):
+			i = renderFence(b, lines, i)
+		case headingRe.MatchString(line):
+			m := headingRe.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + renderInline(strings.TrimSpace(m[2])) + "</h" + level + ">\n")
+			i++
+		case ruleRe.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case isQuote(line):
+			i = renderQuote(b, lines, i)
+		case listMarkerRe.MatchString(line):
+			i = renderList(b, lines, i)
+		case indentOf(line) >= 4:
+			i = renderIndentedCode(b, lines, i)
+		default:
+			i = renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isQuote(line string) bool {
+	return indentOf(line) < 4 && strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// interruptsParagraph сообщает, начинает ли строка новый блок без пустой строки перед ним
+func interruptsParagraph(line string) bool {
+	if fenceRe.MatchString(line) || headingRe.MatchString(line) || ruleRe.MatchString(line) || isQuote(line) {
+		return true
+	}
+	// Нумерованный список прерывает абзац, только если начинается с единицы
+	if m := listMarkerRe.FindStringSubmatch(line); m != nil {
+		return m[3] == "" || m[3] == "1"
+	}
+	return false
+}
+
+func renderFence(b *strings.Builder, lines []string, start int) int {
+	m := fenceRe.FindStringSubmatch(lines[start])
+	indent, fence, lang := len(m[1]), m[2], m[3]
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		if cut := min(indent, indentOf(line)); cut > 0 {
+			line = line[cut:]
+		}
+		code = append(code, line)
+	}
+
+	b.WriteString("<pre><code")
+	if lang != "" {
+		b.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+	}
+	b.WriteString(">")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+
+	return i
+}
+
+func renderIndentedCode(b *strings.Builder, lines []string, start int) int {
+	var code []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			code = append(code, "")
+			continue
+		}
+		if indentOf(line) < 4 {
+			break
+		}
+		code = append(code, line[4:])
+	}
+
+	// Пустые строки в конце блоку кода не принадлежат
+	for len(code) > 0 && code[len(code)-1] == "" {
+		code = code[:len(code)-1]
+	}
+
+	b.WriteString("<pre><code>")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+
+	return i
+}
+
+func renderQuote(b *strings.Builder, lines []string, start int) int {
+	var inner []string
+	i := start
+	for ; i < len(lines) && isQuote(lines[i]); i++ {
+		line := strings.TrimPrefix(strings.TrimLeft(lines[i], " "), ">")
+		inner = append(inner, strings.TrimPrefix(line, " "))
+	}
+
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner, false)
+	b.WriteString("</blockquote>\n")
+
+	return i
+}
+
+type listItem struct {
+	lines []string
+}
+
+func renderList(b *strings.Builder, lines []string, start int) int {
+	first := listMarkerRe.FindStringSubmatch(lines[start])
+	ordered := first[3] != ""
+	listIndent := len(first[1])
+
+	var items []listItem
+	loose := false
+	i := start
+
+	for i < len(lines) {
+		m := listMarkerRe.FindStringSubmatch(lines[i])
+		if m == nil || (m[3] != "") != ordered || len(m[1]) != listIndent || ruleRe.MatchString(lines[i]) {
+			break
+		}
+
+		// Содержимое пункта выравнивается по первому символу после маркера
+		contentIndent := len(m[0])
+		text := lines[i][contentIndent:]
+		if strings.TrimSpace(text) == "" {
+			contentIndent = len(m[1]) + len(m[2]) + 1
+		}
+		item := listItem{lines: []string{text}}
+		i++
+
+		blank := false
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				blank = true
+				item.lines = append(item.lines, "")
+				i++
+				continue
+			}
+			if indentOf(line) >= contentIndent {
+				if blank {
+					loose = true
+				}
+				blank = false
+				item.lines = append(item.lines, line[contentIndent:])
+				i++
+				continue
+			}
+			// Ленивое продолжение абзаца без отступа
+			if !blank && !interruptsParagraph(line) && !listMarkerRe.MatchString(line) {
+				item.lines = append(item.lines, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			break
+		}
+
+		// Хвостовые пустые строки относятся к списку, только если за ними идёт следующий пункт
+		trailing := 0
+		for len(item.lines) > 0 && item.lines[len(item.lines)-1] == "" {
+			item.lines = item.lines[:len(item.lines)-1]
+			trailing++
+		}
+		items = append(items, item)
+
+		if trailing > 0 {
+			next := listMarkerRe.FindStringSubmatch(safeLine(lines, i))
+			if next == nil || (next[3] != "") != ordered || len(next[1]) != listIndent {
+				break
+			}
+			loose = true
+		}
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+
+	b.WriteString("<" + tag)
+	if ordered {
+		if n, err := strconv.Atoi(first[3]); err == nil && n != 1 {
+			b.WriteString(` start="` + strconv.Itoa(n) + `"`)
+		}
+	}
+	b.WriteString(">\n")
+
+	for _, item := range items {
+		b.WriteString("<li>")
+		var inner strings.Builder
+		renderBlocks(&inner, item.lines, !loose)
+		b.WriteString(strings.TrimSuffix(inner.String(), "\n"))
+		b.WriteString("</li>\n")
+	}
+
+	b.WriteString("</" + tag + ">\n")
+
+	return i
+}
+
+func safeLine(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+func renderParagraph(b *strings.Builder, lines []string, start int, tight bool) int {
+	var para []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if len(para) > 0 {
+			// Setext-заголовок: строка из = или - под абзацем
+			if m := setextRe.FindStringSubmatch(line); m != nil {
+				level := "2"
+				if m[1][0] == '=' {
+					level = "1"
+				}
+				text := renderInline(strings.TrimSpace(strings.Join(para, "\n")))
+				b.WriteString("<h" + level + ">" + text + "</h" + level + ">\n")
+				return i + 1
+			}
+			if interruptsParagraph(line) {
+				break
+			}
+		}
+		para = append(para, strings.TrimLeft(line, " "))
+	}
+
+	text := renderInline(strings.TrimRight(strings.Join(para, "\n"), " "))
+	if tight {
+		b.WriteString(text + "\n")
+	} else {
+		b.WriteString("<p>" + text + "</p>\n")
+	}
+
+	return i
+}
@@ -0,0 +1,420 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+// render повторяет путь content_html для формата markdown
+func render(t *testing.T, source string) string {
+	t.Helper()
+	out, err := Sanitize(Markdown(source))
+	if err != nil {
+		t.Fatalf("Sanitize(%q): %v", source, err)
+	}
+	return out
+}
+
+func TestMarkdownUnsafeURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "javascript link",
+			source: "[x](javascript:alert(1))",
+			want:   `<p><a rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "javascript link in mixed case with entities",
+			source: "[x](JaVa&#115;cript:alert(1))",
+			want:   `<p><a rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "javascript link with tab inside scheme",
+			source: "[x](<java\tscript:alert(1)>)",
+			want:   `<p><a rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "data link",
+			source: "[x](data:text/html;base64,PHNjcmlwdD4=)",
+			want:   `<p><a rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "vbscript link",
+			source: "[x](vbscript:msgbox)",
+			want:   `<p><a rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "javascript image",
+			source: "![x](javascript:alert(1))",
+			want:   `<p><img alt="x"></p>`,
+		},
+		{
+			name:   "data image",
+			source: "![x](data:image/svg+xml;base64,PHN2Zz4=)",
+			want:   `<p><img alt="x"></p>`,
+		},
+		{
+			name:   "mailto image",
+			source: "![x](mailto:a@b.c)",
+			want:   `<p><img alt="x"></p>`,
+		},
+		{
+			name:   "javascript autolink",
+			source: "<javascript:alert(1)>",
+			want:   `<p><a rel="nofollow noopener noreferrer">javascript:alert(1)</a></p>`,
+		},
+		{
+			name:   "data autolink",
+			source: "<data:text/html,x>",
+			want:   `<p><a rel="nofollow noopener noreferrer">data:text/html,x</a></p>`,
+		},
+		{
+			name:   "title cannot break out of attribute",
+			source: `[x](https://a.example "a\" onclick=\"alert(1)")`,
+			want:   `<p><a href="https://a.example" title="a&#34; onclick=&#34;alert(1)" rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "https link is kept",
+			source: "[x](https://a.example/?q=1&r=2)",
+			want:   `<p><a href="https://a.example/?q=1&amp;r=2" rel="nofollow noopener noreferrer">x</a></p>`,
+		},
+		{
+			name:   "relative image is kept",
+			source: "![x](/img/a.png)",
+			want:   `<p><img src="/img/a.png" alt="x"></p>`,
+		},
+		{
+			name:   "http autolink is kept",
+			source: "<http://a.example>",
+			want:   `<p><a href="http://a.example" rel="nofollow noopener noreferrer">http://a.example</a></p>`,
+		},
+		{
+			name:   "email autolink",
+			source: "<a@b.example>",
+			want:   `<p><a href="mailto:a@b.example" rel="nofollow noopener noreferrer">a@b.example</a></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, tt.source); got != tt.want {
+				t.Errorf("render(%q)\n got: %s\nwant: %s", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "script is dropped with content",
+			source: `<p>a<script>alert(1)</script>b</p>`,
+			want:   `<p>ab</p>`,
+		},
+		{
+			name:   "style, iframe and svg are dropped",
+			source: `<style>p{}</style><iframe src="https://a.example"></iframe><svg><script>alert(1)</script></svg>x`,
+			want:   `x`,
+		},
+		{
+			name:   "unknown tag is unwrapped",
+			source: `<section><p>a</p></section>`,
+			want:   `<p>a</p>`,
+		},
+		{
+			name:   "event handlers and style are removed",
+			source: `<p onclick="alert(1)" style="color:red">a</p><img src="https://a.example/x.png" onerror="alert(1)">`,
+			want:   `<p>a</p><img src="https://a.example/x.png">`,
+		},
+		{
+			name:   "javascript href with entities and whitespace",
+			source: `<a href=" &#106;avascript:alert(1)">a</a><a href="java&#x09;script:alert(1)">b</a>`,
+			want:   `<a rel="nofollow noopener noreferrer">a</a><a rel="nofollow noopener noreferrer">b</a>`,
+		},
+		{
+			name:   "data src",
+			source: `<img src="data:image/png;base64,AAAA">`,
+			want:   `<img>`,
+		},
+		{
+			name:   "rel and target from source are replaced",
+			source: `<a href="https://a.example" target="_blank" rel="opener">a</a>`,
+			want:   `<a href="https://a.example" rel="nofollow noopener noreferrer">a</a>`,
+		},
+		{
+			name:   "only language classes on code",
+			source: `<code class="language-go">x</code><code class="x onload">y</code><p class="language-go">z</p>`,
+			want:   `<code class="language-go">x</code><code>y</code><p>z</p>`,
+		},
+		{
+			name:   "numeric attributes are validated",
+			source: `<ol start="3"><li>a</li></ol><td colspan="2;x">b</td>`,
+			want:   `<ol start="3"><li>a</li></ol>b`,
+		},
+		{
+			name:   "comments are dropped",
+			source: `a<!-- <script>alert(1)</script> -->b`,
+			want:   `ab`,
+		},
+		{
+			name:   "text is escaped",
+			source: `<p>&lt;script&gt;</p>`,
+			want:   `<p>&lt;script&gt;</p>`,
+		},
+		{
+			name:   "unclosed attribute",
+			source: `<img src="x" alt="a onerror=alert(1)`,
+			want:   ``,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sanitize(tt.source)
+			if err != nil {
+				t.Fatalf("Sanitize: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Sanitize(%q)\n got: %s\nwant: %s", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "emphasis and strong",
+			source: "*a* **b** _c_ __d__ ~~e~~",
+			want:   "<p><em>a</em> <strong>b</strong> <em>c</em> <strong>d</strong> <del>e</del></p>",
+		},
+		{
+			name:   "nested emphasis",
+			source: "**a *b* c**",
+			want:   "<p><strong>a <em>b</em> c</strong></p>",
+		},
+		{
+			name:   "emphasis inside link",
+			source: "[a **b**](https://a.example)",
+			want:   `<p><a href="https://a.example">a <strong>b</strong></a></p>`,
+		},
+		{
+			name:   "link inside emphasis",
+			source: "*see [a](https://a.example)*",
+			want:   `<p><em>see <a href="https://a.example">a</a></em></p>`,
+		},
+		{
+			name:   "nested brackets in label",
+			source: "[a [b] c](https://a.example)",
+			want:   `<p><a href="https://a.example">a [b] c</a></p>`,
+		},
+		{
+			name:   "intraword underscore",
+			source: "snake_case_name",
+			want:   "<p>snake_case_name</p>",
+		},
+		{
+			name:   "unclosed emphasis",
+			source: "*a",
+			want:   "<p>*a</p>",
+		},
+		{
+			name:   "raw html is escaped",
+			source: `<img src=x onerror=alert(1)> & <b>`,
+			want:   "<p>&lt;img src=x onerror=alert(1)&gt; &amp; &lt;b&gt;</p>",
+		},
+		{
+			name:   "entities are kept",
+			source: "&copy; &#169;",
+			want:   "<p>&copy; &#169;</p>",
+		},
+		{
+			name:   "escaped markup",
+			source: `\*a\* \[b\]`,
+			want:   "<p>*a* [b]</p>",
+		},
+		{
+			name:   "code span escapes html and ignores markup",
+			source: "`<script>*a*</script>`",
+			want:   "<p><code>&lt;script&gt;*a*&lt;/script&gt;</code></p>",
+		},
+		{
+			name:   "code span with backticks",
+			source: "`` a`b ``",
+			want:   "<p><code>a`b</code></p>",
+		},
+		{
+			name:   "image alt drops markup",
+			source: `![*a* "b"](/x.png "t")`,
+			want:   `<p><img src="/x.png" alt="a &#34;b&#34;" title="t"></p>`,
+		},
+		{
+			name:   "hard break",
+			source: "a  \nb",
+			want:   "<p>a<br>\nb</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.source); got != tt.want {
+				t.Errorf("Markdown(%q)\n got: %s\nwant: %s", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "fenced code is escaped",
+			source: "```\n<script>alert(1)</script>\n[a](javascript:x) *b*\n```",
+			want:   "<pre><code>&lt;script&gt;alert(1)&lt;/script&gt;\n[a](javascript:x) *b*\n</code></pre>",
+		},
+		{
+			name:   "fenced code with language",
+			source: "~~~go\nif a < b {}\n~~~",
+			want:   "<pre><code class=\"language-go\">if a &lt; b {}\n</code></pre>",
+		},
+		{
+			name:   "language cannot inject attributes",
+			source: "```go\"onclick=\"x\nа\n```",
+			want:   "<pre><code class=\"language-go&#34;onclick=&#34;x\">а\n</code></pre>",
+		},
+		{
+			name:   "unclosed fence runs to the end",
+			source: "```\n<b>a</b>",
+			want:   "<pre><code>&lt;b&gt;a&lt;/b&gt;\n</code></pre>",
+		},
+		{
+			name:   "indented code is escaped",
+			source: "    <div onclick=\"x\">\n    &amp;",
+			want:   "<pre><code>&lt;div onclick=&#34;x&#34;&gt;\n&amp;amp;\n</code></pre>",
+		},
+		{
+			name:   "indented code keeps inner blank lines",
+			source: "    a\n\n    b\n\nc",
+			want:   "<pre><code>a\n\nb\n</code></pre>\n<p>c</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.source); got != tt.want {
+				t.Errorf("Markdown(%q)\n got: %s\nwant: %s", tt.source, got, tt.want)
+			}
+		})
+	}
+
+	// Класс с недопустимыми символами отбрасывается санитайзером
+	if got := render(t, "```go\"onclick=\"x\nа\n```"); strings.Contains(got, "onclick") || strings.Contains(got, "class") {
+		t.Errorf("unsafe code class survived sanitizing: %s", got)
+	}
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "headings",
+			source: "# a #\nb\n---",
+			want:   "<h1>a</h1>\n<h2>b</h2>",
+		},
+		{
+			name:   "tight and loose lists",
+			source: "- a\n- b\n\n1. c\n\n2. d",
+			want:   "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n<ol>\n<li><p>c</p></li>\n<li><p>d</p></li>\n</ol>",
+		},
+		{
+			name:   "ordered list start",
+			source: "3. a",
+			want:   "<ol start=\"3\">\n<li>a</li>\n</ol>",
+		},
+		{
+			name:   "blockquote",
+			source: "> a\n> - b",
+			want:   "<blockquote>\n<p>a</p>\n<ul>\n<li>b</li>\n</ul>\n</blockquote>",
+		},
+		{
+			name:   "rule",
+			source: "a\n\n***",
+			want:   "<p>a</p>\n<hr>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.source); got != tt.want {
+				t.Errorf("Markdown(%q)\n got: %s\nwant: %s", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlain(t *testing.T) {
+	got := Plain("a <b>\nc\n\n\nd & e")
+	want := "<p>a &lt;b&gt;<br>c</p>\n<p>d &amp; e</p>"
+	if got != want {
+		t.Errorf("Plain\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "match is marked",
+			text: "go " + HighlightStart + "channels" + HighlightStop + " explained",
+			want: "go <mark>channels</mark> explained",
+		},
+		{
+			name: "markup in content is escaped",
+			text: `<img src=x onerror=alert(1)> ` + HighlightStart + "xss" + HighlightStop + ` <script>alert(1)</script>`,
+			want: "&lt;img src=x onerror=alert(1)&gt; <mark>xss</mark> &lt;script&gt;alert(1)&lt;/script&gt;",
+		},
+		{
+			name: "highlight tags in content are escaped",
+			text: "<mark>a</mark> " + HighlightStart + "b" + HighlightStop,
+			want: "&lt;mark&gt;a&lt;/mark&gt; <mark>b</mark>",
+		},
+		{
+			name: "unbalanced markers from content stay balanced",
+			text: HighlightStop + "a" + HighlightStart + HighlightStart + "b",
+			want: "a<mark>b</mark>",
+		},
+		{
+			name: "no matches",
+			text: `a & "b"`,
+			want: "a &amp; &#34;b&#34;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text); got != tt.want {
+				t.Errorf("Highlight(%q)\n got: %s\nwant: %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
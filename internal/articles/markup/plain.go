@@ -0,0 +1,22 @@
+package markup
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	paragraphBreakRe = regexp.MustCompile(`\n{2,}`)
+	plainEscaper     = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// Plain оборачивает простой текст в абзацы, одиночные переводы строк становятся <br>.
+// Алгоритм повторяет SQL-миграцию, которой заполнялся content_html существующих статей.
+func Plain(source string) string {
+	text := strings.Trim(strings.ReplaceAll(source, "\r\n", "\n"), " \n")
+	text = plainEscaper.Replace(text)
+	text = paragraphBreakRe.ReplaceAllString(text, "</p><p>")
+	text = strings.ReplaceAll(text, "\n", "<br>")
+	text = strings.ReplaceAll(text, "</p><p>", "</p>\n<p>")
+	return "<p>" + text + "</p>"
+}
@@ -0,0 +1,143 @@
+package markup
+
+import (
+	"fmt"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// allowedTags перечисляет разрешённые элементы и их атрибуты; остальные элементы разворачиваются в содержимое
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"blockquote": nil, "pre": nil, "code": {"class"},
+	"em": nil, "strong": nil, "b": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+	"sub": nil, "sup": nil, "mark": nil, "kbd": nil, "abbr": {"title"},
+	"ul": nil, "ol": {"start"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+	"a":      {"href", "title"},
+	"img":    {"src", "alt", "title", "width", "height"},
+	"figure": nil, "figcaption": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil, "th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+}
+
+// droppedTags удаляются вместе с содержимым
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true, "object": true, "embed": true,
+	"applet": true, "noscript": true, "template": true, "svg": true, "math": true, "form": true, "input": true,
+	"button": true, "textarea": true, "select": true, "option": true, "link": true, "meta": true, "base": true,
+	"title": true, "head": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+var (
+	languageClassRe = regexp.MustCompile(`^language-[a-zA-Z0-9_+#-]{1,30}$`)
+	numberRe        = regexp.MustCompile(`^[0-9]{1,4}$`)
+)
+
+// Sanitize оставляет в HTML только разрешённые элементы и атрибуты и безопасные ссылки
+func Sanitize(source string) (string, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(source), context)
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writeNode(&b, node)
+	}
+	return b.String(), nil
+}
+
+func writeNode(b *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		// Комментарии и doctype отбрасываются
+		return
+	}
+
+	tag := strings.ToLower(node.Data)
+	if droppedTags[tag] || node.Namespace != "" {
+		return
+	}
+
+	attrs, allowed := allowedTags[tag]
+	if !allowed {
+		writeChildren(b, node)
+		return
+	}
+
+	b.WriteString("<" + tag)
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" || !slices.Contains(attrs, attr.Key) {
+			continue
+		}
+		value, ok := sanitizeAttr(tag, attr.Key, attr.Val)
+		if !ok {
+			continue
+		}
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+	}
+	if tag == "a" {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	b.WriteString(">")
+
+	if voidTags[tag] {
+		return
+	}
+
+	writeChildren(b, node)
+	b.WriteString("</" + tag + ">")
+}
+
+func writeChildren(b *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeNode(b, child)
+	}
+}
+
+func sanitizeAttr(tag, key, value string) (string, bool) {
+	switch key {
+	case "href":
+		return safeURL(value, "http", "https", "mailto")
+	case "src":
+		return safeURL(value, "http", "https")
+	case "class":
+		// Разрешены только классы подсветки синтаксиса
+		return value, tag == "code" && languageClassRe.MatchString(value)
+	case "start", "colspan", "rowspan", "width", "height":
+		return value, numberRe.MatchString(value)
+	}
+	return value, true
+}
+
+// safeURL пропускает относительные ссылки и абсолютные с разрешённой схемой
+func safeURL(raw string, schemes ...string) (string, bool) {
+	// Браузеры отбрасывают переводы строк и табуляцию внутри URL, поэтому они удаляются до проверки схемы
+	cleaned := strings.TrimFunc(strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(raw), func(r rune) bool {
+		return r <= ' ' || r == 0x7f
+	})
+	if cleaned == "" {
+		return "", false
+	}
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", false
+	}
+	if u.Scheme == "" {
+		// Ссылка вида "//host" наследует схему страницы и безопасна
+		return cleaned, true
+	}
+	return cleaned, slices.Contains(schemes, strings.ToLower(u.Scheme))
+}
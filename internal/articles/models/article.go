@@ -2,32 +2,87 @@ package models
 
 import "time"
 
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+	ContentFormatPlain    = "plain"
+)
+
+const (
+	TagMatchAny = "any"
+	TagMatchAll = "all"
+)
+
 type Article struct {
-	Id        string
-	Title     string
-	Content   string
-	AuthorId  string
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id            string
+	Slug          string
+	Title         string
+	Content       string
+	ContentFormat string
+	ContentHTML   string
+	AuthorId      string
+	Status        string
+	Tags          []string
+	PublishAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type Tag struct {
+	Name          string
+	ArticlesCount int
 }
 
 type ListArticleParams struct {
 	AuthorId *string
 	Status   *string
+	Tags     []string
+	TagMatch string
+	Before   *Cursor
 	Offset   int
 	Limit    int
 }
 
 type CreateArticleParams struct {
-	Title    string
-	Content  string
-	AuthorId string
-	Status   string
+	Slug          string
+	Title         string
+	Content       string
+	ContentFormat string
+	ContentHTML   string
+	AuthorId      string
+	Status        string
+	Tags          []string
+	PublishAt     *time.Time
 }
 
 type UpdateArticleParams struct {
-	Title   *string
-	Content *string
-	Status  *string
+	Slug           *string
+	Title          *string
+	Content        *string
+	ContentFormat  *string
+	ContentHTML    *string
+	Status         *string
+	Tags           *[]string
+	PublishAt      *time.Time
+	ClearPublishAt bool
+	EditorId       string
+}
+
+type SearchArticleParams struct {
+	Query  string
+	Offset int
+	Limit  int
+}
+
+type SearchResult struct {
+	Article        *Article
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
 }
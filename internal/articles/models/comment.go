@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type Comment struct {
+	Id        string
+	ArticleId string
+	ParentId  *string
+	AuthorId  string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Replies   []*Comment
+}
+
+type ListCommentsParams struct {
+	ArticleId string
+	After     *Cursor
+	Limit     int
+}
+
+type CreateCommentParams struct {
+	ArticleId string
+	ParentId  *string
+	AuthorId  string
+	Content   string
+}
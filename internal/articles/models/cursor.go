@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// Cursor - позиция в выдаче, упорядоченной по (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time
+	Id        string
+}
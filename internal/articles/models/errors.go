@@ -3,5 +3,14 @@ package models
 import "errors"
 
 var (
-	ErrArticleNotFound = errors.New("user not found")
+	ErrArticleNotFound  = errors.New("article not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrForbidden        = errors.New("forbidden")
+	ErrInvalidSchedule  = errors.New("invalid publish schedule")
+	ErrDiffTooLarge     = errors.New("diff too large")
+
+	ErrArticleNotPublished = errors.New("article is not published")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrInvalidParent       = errors.New("invalid parent comment")
+	ErrInvalidCursor       = errors.New("invalid cursor")
 )
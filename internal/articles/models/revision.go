@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Revision struct {
+	Id        string
+	ArticleId string
+	Revision  int
+	Title     string
+	Content   string
+	EditorId  string
+	CreatedAt time.Time
+}
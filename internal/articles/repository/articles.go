@@ -5,16 +5,48 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/markup"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
 	"strings"
 	"time"
 )
 
+// maxSlugAttempts ограничивает повторы вставки при гонке за один и тот же slug
+const maxSlugAttempts = 5
+
 type ArticlesRepository struct {
 	db *pgxpool.Pool
 }
 
+// querier позволяет выполнять одни и те же запросы как в пуле, так и внутри транзакции
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// articleColumns и articleFields должны перечислять поля статьи в одном и том же порядке
+const articleColumns = `id, slug, title, content, content_format, content_html, author_id, status, publish_at, created_at, updated_at`
+
+func articleFields(article *models.Article) []any {
+	return []any{
+		&article.Id,
+		&article.Slug,
+		&article.Title,
+		&article.Content,
+		&article.ContentFormat,
+		&article.ContentHTML,
+		&article.AuthorId,
+		&article.Status,
+		&article.PublishAt,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+	}
+}
+
 func NewArticlesRepository(db *pgxpool.Pool) *ArticlesRepository {
 	return &ArticlesRepository{
 		db: db,
@@ -23,19 +55,49 @@ func NewArticlesRepository(db *pgxpool.Pool) *ArticlesRepository {
 
 func (a *ArticlesRepository) CreateArticle(ctx context.Context, params models.CreateArticleParams) (*models.Article, error) {
 	article := models.Article{
-		Title:    params.Title,
-		Content:  params.Content,
-		AuthorId: params.AuthorId,
-		Status:   params.Status,
+		Title:         params.Title,
+		Content:       params.Content,
+		ContentFormat: params.ContentFormat,
+		ContentHTML:   params.ContentHTML,
+		AuthorId:      params.AuthorId,
+		Status:        params.Status,
+		Tags:          params.Tags,
+		PublishAt:     params.PublishAt,
 	}
-	q := `INSERT INTO articles(title, content, author_id, status) 
-			VALUES ($1, $2, $3, $4)
-			RETURNING id, created_at, updated_at`
 
-	err := a.db.QueryRow(ctx, q, params.Title, params.Content, params.AuthorId, params.Status).
-		Scan(&article.Id, &article.CreatedAt, &article.UpdatedAt)
+	tx, err := a.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed QueryRow: %w", err)
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Параллельная статья с тем же заголовком может занять slug между проверкой и вставкой;
+	// тогда slug подбирается заново - конкурент к этому моменту уже закоммичен и виден
+	for attempt := 1; ; attempt++ {
+		article.Slug, err = a.uniqueSlug(ctx, tx, params.Slug, "")
+		if err != nil {
+			return nil, err
+		}
+
+		err = a.insertArticle(ctx, tx, &article, params)
+		if err == nil {
+			break
+		}
+		if !isSlugConflict(err) || attempt == maxSlugAttempts {
+			return nil, fmt.Errorf("failed QueryRow: %w", err)
+		}
+	}
+
+	if err = a.setArticleTags(ctx, tx, article.Id, params.Tags); err != nil {
+		return nil, err
+	}
+
+	if err = a.createRevision(ctx, tx, article.Id, params.AuthorId); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
 	}
 
 	return &article, nil
@@ -43,17 +105,23 @@ func (a *ArticlesRepository) CreateArticle(ctx context.Context, params models.Cr
 
 func (a *ArticlesRepository) UpdateArticle(ctx context.Context, id string, update models.UpdateArticleParams) (*models.Article, error) {
 	var article models.Article
-	q, args := a.buildUpdateQuery(id, update)
 
-	err := a.db.QueryRow(ctx, q, args...).Scan(
-		&article.Id,
-		&article.Title,
-		&article.Content,
-		&article.AuthorId,
-		&article.Status,
-		&article.CreatedAt,
-		&article.UpdatedAt,
-	)
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if update.Slug != nil {
+		slug, err := a.changeSlug(ctx, tx, id, *update.Slug)
+		if err != nil {
+			return nil, err
+		}
+		update.Slug = &slug
+	}
+
+	q, args := a.buildUpdateQuery(id, update)
+	err = tx.QueryRow(ctx, q, args...).Scan(articleFields(&article)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrArticleNotFound
@@ -61,6 +129,26 @@ func (a *ArticlesRepository) UpdateArticle(ctx context.Context, id string, updat
 		return nil, err
 	}
 
+	if update.Tags != nil {
+		if err = a.setArticleTags(ctx, tx, id, *update.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Title != nil || update.Content != nil {
+		if err = a.createRevision(ctx, tx, id, update.EditorId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = a.attachTags(ctx, tx, []*models.Article{&article}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+
 	return &article, nil
 }
 
@@ -68,6 +156,10 @@ func (a *ArticlesRepository) buildUpdateQuery(id string, updates models.UpdateAr
 	query := `UPDATE articles SET updated_at = $1`
 	args := []interface{}{time.Now()}
 
+	if updates.Slug != nil {
+		args = append(args, *updates.Slug)
+		query += fmt.Sprintf(", slug = $%d", len(args))
+	}
 	if updates.Title != nil {
 		args = append(args, *updates.Title)
 		query += fmt.Sprintf(", title = $%d", len(args))
@@ -76,42 +168,176 @@ func (a *ArticlesRepository) buildUpdateQuery(id string, updates models.UpdateAr
 		args = append(args, *updates.Content)
 		query += fmt.Sprintf(", content = $%d", len(args))
 	}
+	if updates.ContentFormat != nil {
+		args = append(args, *updates.ContentFormat)
+		query += fmt.Sprintf(", content_format = $%d", len(args))
+	}
+	if updates.ContentHTML != nil {
+		args = append(args, *updates.ContentHTML)
+		query += fmt.Sprintf(", content_html = $%d", len(args))
+	}
 	if updates.Status != nil {
 		args = append(args, *updates.Status)
 		query += fmt.Sprintf(", status = $%d", len(args))
 	}
+	if updates.ClearPublishAt {
+		query += ", publish_at = NULL"
+	} else if updates.PublishAt != nil {
+		args = append(args, *updates.PublishAt)
+		query += fmt.Sprintf(", publish_at = $%d", len(args))
+	}
 
 	args = append(args, id)
 	query += fmt.Sprintf(
-		` WHERE id = $%d RETURNING id, title, content, author_id, status, created_at, updated_at`, len(args))
+		` WHERE id = $%d RETURNING `+articleColumns, len(args))
 
 	return query, args
 }
 
 func (a *ArticlesRepository) GetArticleById(ctx context.Context, id string) (*models.Article, error) {
 	var article models.Article
-	q := `SELECT id, title, content, author_id, status, created_at, updated_at
+	q := `SELECT ` + articleColumns + `
 			FROM articles
 			WHERE id = $1`
 
-	err := a.db.QueryRow(ctx, q, id).Scan(
-		&article.Id,
-		&article.Title,
-		&article.Content,
-		&article.AuthorId,
-		&article.Status,
-		&article.CreatedAt,
-		&article.UpdatedAt,
-	)
+	err := a.db.QueryRow(ctx, q, id).Scan(articleFields(&article)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	if err = a.attachTags(ctx, a.db, []*models.Article{&article}); err != nil {
+		return nil, err
+	}
+
+	return &article, nil
+}
+
+func (a *ArticlesRepository) GetArticleBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	var article models.Article
+	q := `SELECT ` + articleColumns + `
+			FROM articles
+			WHERE slug = $1`
+
+	err := a.db.QueryRow(ctx, q, slug).Scan(articleFields(&article)...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrArticleNotFound
 		}
 		return nil, err
 	}
+
+	if err = a.attachTags(ctx, a.db, []*models.Article{&article}); err != nil {
+		return nil, err
+	}
+
 	return &article, nil
 }
 
+// GetCurrentSlug возвращает актуальный slug статьи по одному из её прежних slug
+func (a *ArticlesRepository) GetCurrentSlug(ctx context.Context, oldSlug string) (string, error) {
+	var slug string
+	q := `SELECT a.slug
+			FROM article_slugs s
+			JOIN articles a ON a.id = s.article_id
+			WHERE s.slug = $1`
+
+	err := a.db.QueryRow(ctx, q, oldSlug).Scan(&slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrArticleNotFound
+		}
+		return "", err
+	}
+	return slug, nil
+}
+
+// insertArticle вставляет статью в savepoint, чтобы после конфликта slug транзакция оставалась рабочей
+func (a *ArticlesRepository) insertArticle(ctx context.Context, tx pgx.Tx, article *models.Article, params models.CreateArticleParams) error {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin savepoint: %w", err)
+	}
+	defer savepoint.Rollback(ctx)
+
+	q := `INSERT INTO articles(slug, title, content, content_format, content_html, author_id, status, publish_at) 
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id, created_at, updated_at`
+
+	err = savepoint.QueryRow(ctx, q, article.Slug, params.Title, params.Content, params.ContentFormat, params.ContentHTML,
+		params.AuthorId, params.Status, params.PublishAt).
+		Scan(&article.Id, &article.CreatedAt, &article.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return savepoint.Commit(ctx)
+}
+
+func isSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_articles_slug"
+}
+
+// uniqueSlug подбирает свободный slug, добавляя числовой суффикс при коллизии.
+// Занятыми считаются как актуальные, так и прежние slug других статей.
+func (a *ArticlesRepository) uniqueSlug(ctx context.Context, q querier, base, articleId string) (string, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM articles WHERE slug = $1 AND id::text <> $2)
+		    OR EXISTS(SELECT 1 FROM article_slugs WHERE slug = $1 AND article_id::text <> $2)`
+
+	for i := 1; ; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var taken bool
+		if err := q.QueryRow(ctx, query, candidate, articleId).Scan(&taken); err != nil {
+			return "", fmt.Errorf("checking slug: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+}
+
+// changeSlug переводит статью на новый slug, сохраняя текущий как редирект
+func (a *ArticlesRepository) changeSlug(ctx context.Context, q querier, articleId, base string) (string, error) {
+	var current string
+	err := q.QueryRow(ctx, `SELECT slug FROM articles WHERE id = $1 FOR UPDATE`, articleId).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrArticleNotFound
+		}
+		return "", err
+	}
+
+	slug, err := a.uniqueSlug(ctx, q, base, articleId)
+	if err != nil {
+		return "", err
+	}
+	if slug == current {
+		return slug, nil
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO article_slugs(slug, article_id) VALUES ($1, $2)
+		ON CONFLICT (slug) DO NOTHING`, current, articleId)
+	if err != nil {
+		return "", fmt.Errorf("saving old slug: %w", err)
+	}
+
+	// Статья могла вернуться к одному из своих прежних slug
+	if _, err = q.Exec(ctx, `DELETE FROM article_slugs WHERE slug = $1`, slug); err != nil {
+		return "", fmt.Errorf("releasing slug: %w", err)
+	}
+
+	return slug, nil
+}
+
 func (a *ArticlesRepository) DeleteArticle(ctx context.Context, id string) error {
 	q := `DELETE FROM articles WHERE id = $1`
 	_, err := a.db.Exec(ctx, q, id)
@@ -134,15 +360,7 @@ func (a *ArticlesRepository) ListArticles(ctx context.Context, params models.Lis
 
 	for rows.Next() {
 		var art models.Article
-		err = rows.Scan(
-			&art.Id,
-			&art.Title,
-			&art.Content,
-			&art.AuthorId,
-			&art.Status,
-			&art.CreatedAt,
-			&art.UpdatedAt,
-		)
+		err = rows.Scan(articleFields(&art)...)
 		if err != nil {
 			return nil, fmt.Errorf("scanning article: %w", err)
 		}
@@ -153,11 +371,54 @@ func (a *ArticlesRepository) ListArticles(ctx context.Context, params models.Lis
 		return nil, fmt.Errorf("rows error: %w", err)
 	}
 
+	if err = a.attachTags(ctx, a.db, articles); err != nil {
+		return nil, err
+	}
+
 	return articles, nil
 
 }
 
+func (a *ArticlesRepository) CountArticles(ctx context.Context, params models.ListArticleParams) (int, error) {
+	whereClauses, values := a.buildListFilters(params)
+
+	q := `SELECT COUNT(*) FROM articles`
+	if len(whereClauses) > 0 {
+		q += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var total int
+	if err := a.db.QueryRow(ctx, q, values...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("counting articles: %w", err)
+	}
+	return total, nil
+}
+
 func (a *ArticlesRepository) buildListQuery(reqParams models.ListArticleParams) (string, []interface{}) {
+	whereClauses, values := a.buildListFilters(reqParams)
+
+	// Keyset-пагинация: статьи строго «старше» последней выданной
+	if reqParams.Before != nil {
+		values = append(values, reqParams.Before.CreatedAt, reqParams.Before.Id)
+		whereClauses = append(whereClauses, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(values)-1, len(values)))
+	}
+
+	q := `SELECT ` + articleColumns + ` FROM articles`
+
+	if len(whereClauses) > 0 {
+		q += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	q += " ORDER BY created_at DESC, id DESC"
+
+	values = append(values, reqParams.Limit, reqParams.Offset)
+	q += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(values)-1, len(values))
+
+	return q, values
+}
+
+// buildListFilters собирает условия фильтрации, общие для выборки и подсчёта статей
+func (a *ArticlesRepository) buildListFilters(reqParams models.ListArticleParams) ([]string, []interface{}) {
 	var whereClauses []string
 	var values []interface{}
 
@@ -171,23 +432,80 @@ func (a *ArticlesRepository) buildListQuery(reqParams models.ListArticleParams)
 		whereClauses = append(whereClauses, fmt.Sprintf("status = $%d", len(values)))
 	}
 
-	q := `SELECT id, title, content, author_id, status, created_at, updated_at FROM articles`
+	if len(reqParams.Tags) > 0 {
+		values = append(values, reqParams.Tags)
+		tagsClause := fmt.Sprintf(`id IN (SELECT at.article_id FROM article_tags at
+			JOIN tags t ON t.id = at.tag_id
+			WHERE t.name = ANY($%d)`, len(values))
 
-	if len(whereClauses) > 0 {
-		q += " WHERE " + strings.Join(whereClauses, " AND ")
+		if reqParams.TagMatch == models.TagMatchAll {
+			values = append(values, len(reqParams.Tags))
+			tagsClause += fmt.Sprintf(" GROUP BY at.article_id HAVING COUNT(DISTINCT t.name) = $%d", len(values))
+		}
+
+		whereClauses = append(whereClauses, tagsClause+")")
 	}
 
-	q += " ORDER BY created_at DESC"
+	return whereClauses, values
+}
 
-	values = append(values, reqParams.Limit, reqParams.Offset)
-	q += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(values)-1, len(values))
+// SearchArticles ищет среди опубликованных статей; подсветка считается только для выбранной страницы
+func (a *ArticlesRepository) SearchArticles(ctx context.Context, params models.SearchArticleParams) ([]*models.SearchResult, error) {
+	q := `
+        SELECT r.id, r.slug, r.title, r.content, r.content_format, r.content_html, r.author_id, r.status, r.publish_at, r.created_at, r.updated_at, r.rank,
+               ts_headline('russian', r.title, r.query, $4),
+               ts_headline('russian', r.content, r.query, $5)
+        FROM (
+            SELECT a.id, a.slug, a.title, a.content, a.content_format, a.content_html, a.author_id, a.status, a.publish_at, a.created_at, a.updated_at,
+                   ts_rank_cd(a.search_vector, q.query) AS rank, q.query
+            FROM articles a, websearch_to_tsquery('russian', $1) AS q(query)
+            WHERE a.status = 'published' AND a.search_vector @@ q.query
+            ORDER BY rank DESC, a.created_at DESC
+            LIMIT $2 OFFSET $3
+        ) r
+        ORDER BY r.rank DESC, r.created_at DESC`
+
+	// ts_headline не экранирует исходный текст, поэтому совпадения отмечаются маркерами,
+	// а текст экранируется и маркеры заменяются на <mark> в markup.Highlight
+	selectors := fmt.Sprintf(`StartSel="%s", StopSel="%s"`, markup.HighlightStart, markup.HighlightStop)
+	titleOptions := "HighlightAll=true, " + selectors
+	snippetOptions := selectors + `, MaxFragments=2, MaxWords=35, MinWords=15, FragmentDelimiter=" ... "`
+
+	rows, err := a.db.Query(ctx, q, params.Query, params.Limit, params.Offset, titleOptions, snippetOptions)
+	if err != nil {
+		return nil, fmt.Errorf("searching articles: %w", err)
+	}
+	defer rows.Close()
 
-	return q, values
+	var results []*models.SearchResult
+	var articles []*models.Article
+	for rows.Next() {
+		var article models.Article
+		result := models.SearchResult{Article: &article}
+		dest := append(articleFields(&article), &result.Rank, &result.TitleHighlight, &result.Snippet)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scanning search result: %w", err)
+		}
+		result.TitleHighlight = markup.Highlight(result.TitleHighlight)
+		result.Snippet = markup.Highlight(result.Snippet)
+		results = append(results, &result)
+		articles = append(articles, &article)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	if err = a.attachTags(ctx, a.db, articles); err != nil {
+		return nil, err
+	}
+
+	return results, nil
 }
 
 func (a *ArticlesRepository) GetLatestArticles(ctx context.Context, limit int) ([]*models.Article, error) {
 	q := `
-        SELECT id, title, content, author_id, status, created_at, updated_at 
+        SELECT ` + articleColumns + `
         FROM articles 
 --         WHERE status = 'published'
         ORDER BY created_at DESC 
@@ -202,15 +520,49 @@ func (a *ArticlesRepository) GetLatestArticles(ctx context.Context, limit int) (
 
 	for rows.Next() {
 		var article models.Article
-		if err = rows.Scan(
-			&article.Id,
-			&article.Title,
-			&article.Content,
-			&article.AuthorId,
-			&article.Status,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-		); err != nil {
+		if err = rows.Scan(articleFields(&article)...); err != nil {
+			return nil, fmt.Errorf("scanning rows: %w", err)
+		}
+		articles = append(articles, &article)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	if err = a.attachTags(ctx, a.db, articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// PublishDueArticles публикует черновики, у которых наступило время publish_at.
+// SKIP LOCKED позволяет нескольким репликам сервиса обрабатывать очередь без двойной публикации.
+func (a *ArticlesRepository) PublishDueArticles(ctx context.Context, now time.Time, limit int) ([]*models.Article, error) {
+	q := `
+        WITH due AS (
+            SELECT id FROM articles
+            WHERE status = 'draft' AND publish_at IS NOT NULL AND publish_at <= $1
+            ORDER BY publish_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        UPDATE articles a SET status = 'published', updated_at = $1
+        FROM due
+        WHERE a.id = due.id
+        RETURNING a.id, a.slug, a.title, a.content, a.content_format, a.content_html, a.author_id, a.status, a.publish_at, a.created_at, a.updated_at`
+
+	var articles []*models.Article
+	rows, err := a.db.Query(ctx, q, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("publishing due articles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article models.Article
+		if err = rows.Scan(articleFields(&article)...); err != nil {
 			return nil, fmt.Errorf("scanning rows: %w", err)
 		}
 		articles = append(articles, &article)
@@ -222,3 +574,100 @@ func (a *ArticlesRepository) GetLatestArticles(ctx context.Context, limit int) (
 
 	return articles, nil
 }
+
+func (a *ArticlesRepository) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	q := `
+        SELECT t.name, COUNT(at.article_id)
+        FROM tags t
+        JOIN article_tags at ON at.tag_id = t.id
+        JOIN articles a ON a.id = at.article_id AND a.status = 'published'
+        GROUP BY t.id, t.name
+        ORDER BY COUNT(at.article_id) DESC, t.name`
+
+	var tags []*models.Tag
+	rows, err := a.db.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("querying tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag models.Tag
+		if err = rows.Scan(&tag.Name, &tag.ArticlesCount); err != nil {
+			return nil, fmt.Errorf("scanning tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// setArticleTags полностью заменяет набор тегов статьи, создавая недостающие теги
+func (a *ArticlesRepository) setArticleTags(ctx context.Context, q querier, articleId string, tags []string) error {
+	if _, err := q.Exec(ctx, `DELETE FROM article_tags WHERE article_id = $1`, articleId); err != nil {
+		return fmt.Errorf("clearing article tags: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := q.Exec(ctx, `INSERT INTO tags(name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, tags)
+	if err != nil {
+		return fmt.Errorf("inserting tags: %w", err)
+	}
+
+	_, err = q.Exec(ctx, `
+		INSERT INTO article_tags(article_id, tag_id)
+		SELECT $1, id FROM tags WHERE name = ANY($2)`, articleId, tags)
+	if err != nil {
+		return fmt.Errorf("linking article tags: %w", err)
+	}
+
+	return nil
+}
+
+// attachTags подгружает теги для уже выбранных статей одним запросом
+func (a *ArticlesRepository) attachTags(ctx context.Context, q querier, articles []*models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(articles))
+	byId := make(map[string]*models.Article, len(articles))
+	for i, article := range articles {
+		ids[i] = article.Id
+		byId[article.Id] = article
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT at.article_id, t.name
+		FROM article_tags at
+		JOIN tags t ON t.id = at.tag_id
+		WHERE at.article_id = ANY($1::uuid[])
+		ORDER BY t.name`, ids)
+	if err != nil {
+		return fmt.Errorf("querying article tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId, name string
+		if err = rows.Scan(&articleId, &name); err != nil {
+			return fmt.Errorf("scanning article tag: %w", err)
+		}
+		if article, ok := byId[articleId]; ok {
+			article.Tags = append(article.Tags, name)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("rows: %w", err)
+	}
+
+	return nil
+}
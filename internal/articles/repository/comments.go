@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+)
+
+type CommentsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCommentsRepository(db *pgxpool.Pool) *CommentsRepository {
+	return &CommentsRepository{
+		db: db,
+	}
+}
+
+func (c *CommentsRepository) CreateComment(ctx context.Context, params models.CreateCommentParams) (*models.Comment, error) {
+	comment := models.Comment{
+		ArticleId: params.ArticleId,
+		ParentId:  params.ParentId,
+		AuthorId:  params.AuthorId,
+		Content:   params.Content,
+	}
+	q := `INSERT INTO comments(article_id, parent_id, author_id, content)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, created_at, updated_at`
+
+	err := c.db.QueryRow(ctx, q, params.ArticleId, params.ParentId, params.AuthorId, params.Content).
+		Scan(&comment.Id, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed QueryRow: %w", err)
+	}
+
+	return &comment, nil
+}
+
+func (c *CommentsRepository) GetCommentById(ctx context.Context, id string) (*models.Comment, error) {
+	var comment models.Comment
+	q := `SELECT id, article_id, parent_id, author_id, content, created_at, updated_at
+			FROM comments
+			WHERE id = $1`
+
+	err := c.db.QueryRow(ctx, q, id).Scan(
+		&comment.Id,
+		&comment.ArticleId,
+		&comment.ParentId,
+		&comment.AuthorId,
+		&comment.Content,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments возвращает страницу комментариев верхнего уровня вместе со всеми ответами на них
+func (c *CommentsRepository) ListComments(ctx context.Context, params models.ListCommentsParams) ([]*models.Comment, error) {
+	q := `SELECT id, article_id, parent_id, author_id, content, created_at, updated_at
+			FROM comments
+			WHERE article_id = $1 AND parent_id IS NULL`
+	args := []interface{}{params.ArticleId}
+
+	if params.After != nil {
+		args = append(args, params.After.CreatedAt, params.After.Id)
+		q += fmt.Sprintf(" AND (created_at, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	args = append(args, params.Limit)
+	q += fmt.Sprintf(" ORDER BY created_at, id LIMIT $%d", len(args))
+
+	comments, err := c.queryComments(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(comments) == 0 {
+		return comments, nil
+	}
+
+	parentIds := make([]string, len(comments))
+	byId := make(map[string]*models.Comment, len(comments))
+	for i, comment := range comments {
+		parentIds[i] = comment.Id
+		byId[comment.Id] = comment
+	}
+
+	replies, err := c.queryComments(ctx, `
+		SELECT id, article_id, parent_id, author_id, content, created_at, updated_at
+		FROM comments
+		WHERE parent_id = ANY($1::uuid[])
+		ORDER BY created_at, id`, parentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		if parent, ok := byId[*reply.ParentId]; ok {
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+
+	return comments, nil
+}
+
+func (c *CommentsRepository) DeleteComment(ctx context.Context, id string) error {
+	q := `DELETE FROM comments WHERE id = $1`
+	result, err := c.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrCommentNotFound
+	}
+	return nil
+}
+
+func (c *CommentsRepository) queryComments(ctx context.Context, q string, args ...interface{}) ([]*models.Comment, error) {
+	var comments []*models.Comment
+
+	rows, err := c.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var comment models.Comment
+		if err = rows.Scan(
+			&comment.Id,
+			&comment.ArticleId,
+			&comment.ParentId,
+			&comment.AuthorId,
+			&comment.Content,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning comment: %w", err)
+		}
+		comments = append(comments, &comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+)
+
+// createRevision сохраняет текущее состояние статьи следующей по счёту ревизией.
+// Вызывается в транзакции после изменения статьи, поэтому строка статьи уже заблокирована.
+func (a *ArticlesRepository) createRevision(ctx context.Context, q querier, articleId, editorId string) error {
+	query := `
+		INSERT INTO article_revisions (article_id, revision, title, content, editor_id)
+		SELECT id,
+		       COALESCE((SELECT MAX(revision) FROM article_revisions WHERE article_id = $1), 0) + 1,
+		       title, content, $2
+		FROM articles
+		WHERE id = $1`
+
+	if _, err := q.Exec(ctx, query, articleId, editorId); err != nil {
+		return fmt.Errorf("creating revision: %w", err)
+	}
+	return nil
+}
+
+func (a *ArticlesRepository) ListRevisions(ctx context.Context, articleId string) ([]*models.Revision, error) {
+	q := `
+        SELECT id, article_id, revision, title, editor_id, created_at
+        FROM article_revisions
+        WHERE article_id = $1
+        ORDER BY revision DESC`
+
+	var revisions []*models.Revision
+	rows, err := a.db.Query(ctx, q, articleId)
+	if err != nil {
+		return nil, fmt.Errorf("querying revisions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision models.Revision
+		if err = rows.Scan(
+			&revision.Id,
+			&revision.ArticleId,
+			&revision.Revision,
+			&revision.Title,
+			&revision.EditorId,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning revision: %w", err)
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	return revisions, nil
+}
+
+func (a *ArticlesRepository) GetRevision(ctx context.Context, articleId string, revisionNum int) (*models.Revision, error) {
+	var revision models.Revision
+	q := `SELECT id, article_id, revision, title, content, editor_id, created_at
+			FROM article_revisions
+			WHERE article_id = $1 AND revision = $2`
+
+	err := a.db.QueryRow(ctx, q, articleId, revisionNum).Scan(
+		&revision.Id,
+		&revision.ArticleId,
+		&revision.Revision,
+		&revision.Title,
+		&revision.Content,
+		&revision.EditorId,
+		&revision.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
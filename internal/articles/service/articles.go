@@ -2,47 +2,86 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
 	"go.uber.org/zap"
+	"strings"
+	"time"
 )
 
 type ArticlesRepo interface {
 	CreateArticle(ctx context.Context, params models.CreateArticleParams) (*models.Article, error)
 	UpdateArticle(ctx context.Context, id string, update models.UpdateArticleParams) (*models.Article, error)
 	GetArticleById(ctx context.Context, id string) (*models.Article, error)
+	GetArticleBySlug(ctx context.Context, slug string) (*models.Article, error)
+	GetCurrentSlug(ctx context.Context, oldSlug string) (string, error)
 	DeleteArticle(ctx context.Context, id string) error
 	ListArticles(ctx context.Context, params models.ListArticleParams) ([]*models.Article, error)
+	CountArticles(ctx context.Context, params models.ListArticleParams) (int, error)
 	GetLatestArticles(ctx context.Context, limit int) ([]*models.Article, error)
+	ListTags(ctx context.Context) ([]*models.Tag, error)
+	SearchArticles(ctx context.Context, params models.SearchArticleParams) ([]*models.SearchResult, error)
+	ListRevisions(ctx context.Context, articleId string) ([]*models.Revision, error)
+	GetRevision(ctx context.Context, articleId string, revisionNum int) (*models.Revision, error)
 }
 
 type ArticlesCache interface {
 	GetLatestArticles(ctx context.Context) ([]*models.Article, error)
 	SetLatestArticles(ctx context.Context, articles []*models.Article) error
 	InvalidateLatestArticles(ctx context.Context) error
+	InvalidateFeeds(ctx context.Context, authorId string) error
 }
 
 type ArticlesService struct {
-	log   *zap.SugaredLogger
-	repo  ArticlesRepo
-	cache ArticlesCache
+	log         *zap.SugaredLogger
+	repo        ArticlesRepo
+	cache       ArticlesCache
+	authors     AuthorsProvider
+	permissions PermissionChecker
 }
 
-func NewArticlesService(log *zap.SugaredLogger, repo ArticlesRepo, cache ArticlesCache) *ArticlesService {
+func NewArticlesService(log *zap.SugaredLogger,
+	repo ArticlesRepo,
+	cache ArticlesCache,
+	authors AuthorsProvider,
+	permissions PermissionChecker,
+) *ArticlesService {
 	return &ArticlesService{
-		log:   log,
-		repo:  repo,
-		cache: cache,
+		log:         log,
+		repo:        repo,
+		cache:       cache,
+		authors:     authors,
+		permissions: permissions,
 	}
 }
 
 func (a *ArticlesService) CreateArticle(ctx context.Context, req dto.CreateRequest) (*dto.ArticleResponse, error) {
+	if err := validateSchedule(req.PublishAt, req.Status); err != nil {
+		return nil, err
+	}
+
+	format := req.ContentFormat
+	if format == "" {
+		format = models.ContentFormatMarkdown
+	}
+	contentHTML, err := renderContent(format, req.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render content: %w", err)
+	}
+
 	params := models.CreateArticleParams{
-		Title:    req.Title,
-		Content:  req.Content,
-		AuthorId: req.AuthorId,
-		Status:   req.Status,
+		Slug:          slugify(req.Title),
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: format,
+		ContentHTML:   contentHTML,
+		AuthorId:      req.AuthorId,
+		Status:        req.Status,
+		Tags:          normalizeTags(req.Tags),
+		PublishAt:     req.PublishAt,
 	}
 
 	article, err := a.repo.CreateArticle(ctx, params)
@@ -50,11 +89,10 @@ func (a *ArticlesService) CreateArticle(ctx context.Context, req dto.CreateReque
 		return nil, fmt.Errorf("failed Create Article: %w", err)
 	}
 
-	if err = a.cache.InvalidateLatestArticles(ctx); err != nil {
-		a.log.Error("Failed to invalidate cache", "error", err)
-	}
+	a.invalidateCache(ctx, article.AuthorId)
 
 	resp := dto.FromArticleModel(article)
+	withArticleAuthors(ctx, a.log, a.authors, &resp)
 	return &resp, nil
 }
 
@@ -65,23 +103,52 @@ func (a *ArticlesService) GetArticle(ctx context.Context, id string) (*dto.Artic
 	}
 
 	resp := dto.FromArticleModel(article)
+	withArticleAuthors(ctx, a.log, a.authors, &resp)
 	return &resp, nil
 }
 
-func (a *ArticlesService) DeleteArticle(ctx context.Context, articleId string, userID string) error {
+// GetArticleBySlug возвращает статью по актуальному slug, а для прежнего slug - актуальный slug для редиректа
+func (a *ArticlesService) GetArticleBySlug(ctx context.Context, slug string) (*dto.ArticleResponse, string, error) {
+	article, err := a.repo.GetArticleBySlug(ctx, slug)
+	if err == nil {
+		resp := dto.FromArticleModel(article)
+		withArticleAuthors(ctx, a.log, a.authors, &resp)
+		return &resp, "", nil
+	}
+	if !errors.Is(err, models.ErrArticleNotFound) {
+		return nil, "", fmt.Errorf("failed GetArticleBySlug: %w", err)
+	}
+
+	currentSlug, err := a.repo.GetCurrentSlug(ctx, slug)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed GetCurrentSlug: %w", err)
+	}
+
+	return nil, currentSlug, nil
+}
+
+func (a *ArticlesService) DeleteArticle(ctx context.Context, articleId, userID, role string) error {
 	article, err := a.repo.GetArticleById(ctx, articleId)
 	if err != nil {
 		return fmt.Errorf("failed to get article: %w", err)
 	}
 
 	if article.AuthorId != userID {
-		return fmt.Errorf("forbidden: only author can delete article")
+		allowed, err := canOverride(ctx, a.permissions, userID, role, authz.ArticlesDeleteAny)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("%w: only author or admin can delete article", models.ErrForbidden)
+		}
 	}
 
 	err = a.repo.DeleteArticle(ctx, articleId)
 	if err != nil {
 		return fmt.Errorf("failed DeleteArticle: %w", err)
 	}
+
+	a.invalidateCache(ctx, article.AuthorId)
 	return nil
 }
 
@@ -90,8 +157,17 @@ func (a *ArticlesService) ListArticles(ctx context.Context, req dto.ListRequest)
 	params := models.ListArticleParams{
 		AuthorId: req.AuthorID,
 		Status:   req.Status,
+		Tags:     normalizeTags(req.Tags),
+		TagMatch: req.TagMatch,
 		Offset:   req.Offset,
-		Limit:    req.Limit,
+		Limit:    req.Limit + 1,
+	}
+	if req.Cursor != "" {
+		cursor, err := dto.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.Before = cursor
 	}
 
 	articles, err := a.repo.ListArticles(ctx, params)
@@ -99,28 +175,123 @@ func (a *ArticlesService) ListArticles(ctx context.Context, req dto.ListRequest)
 		return nil, fmt.Errorf("failed ListArticles: %w", err)
 	}
 
-	response := dto.FromArticleModels(articles, req.Offset, req.Limit)
+	total, err := a.repo.CountArticles(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed CountArticles: %w", err)
+	}
+
+	// Лишняя запись показывает, что есть следующая страница; курсор выдаётся и в режиме offset,
+	// чтобы клиент мог перейти на keyset-пагинацию с любой страницы
+	var nextCursor string
+	if len(articles) > req.Limit {
+		articles = articles[:req.Limit]
+		last := articles[len(articles)-1]
+		nextCursor = dto.EncodeCursor(models.Cursor{CreatedAt: last.CreatedAt, Id: last.Id})
+	}
+
+	response := dto.FromArticleModels(articles, total, req.Offset, req.Limit, nextCursor)
+	articleResponses := make([]*dto.ArticleResponse, len(response.Articles))
+	for i := range response.Articles {
+		articleResponses[i] = &response.Articles[i]
+	}
+	withArticleAuthors(ctx, a.log, a.authors, articleResponses...)
+	return &response, nil
+}
+
+func (a *ArticlesService) SearchArticles(ctx context.Context, req dto.SearchRequest) (*dto.SearchResponse, error) {
+	params := models.SearchArticleParams{
+		Query:  req.Query,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}
+
+	results, err := a.repo.SearchArticles(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed SearchArticles: %w", err)
+	}
+
+	response := dto.FromSearchResults(results, req.Query, req.Offset, req.Limit)
+	articleResponses := make([]*dto.ArticleResponse, len(response.Results))
+	for i := range response.Results {
+		articleResponses[i] = &response.Results[i].ArticleResponse
+	}
+	withArticleAuthors(ctx, a.log, a.authors, articleResponses...)
 	return &response, nil
 }
 
 func (a *ArticlesService) UpdateArticle(ctx context.Context,
 	articleId string,
 	req dto.UpdateRequest,
-	userID string,
+	userID, role string,
 ) (*dto.ArticleResponse, error) {
 	article, err := a.repo.GetArticleById(ctx, articleId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
+	// Администратор может править чужие статьи, автор при этом не меняется
 	if article.AuthorId != userID {
-		return nil, fmt.Errorf("forbidden: only author can update article")
+		allowed, err := canOverride(ctx, a.permissions, userID, role, authz.ArticlesUpdateAny)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: only author or admin can update article", models.ErrForbidden)
+		}
+	}
+
+	status := article.Status
+	if req.Status != nil {
+		status = *req.Status
+	}
+	if req.ClearPublishAt && req.PublishAt != nil {
+		return nil, fmt.Errorf("%w: publish_at and clear_publish_at are mutually exclusive", models.ErrInvalidSchedule)
+	}
+	if err = validateSchedule(req.PublishAt, status); err != nil {
+		return nil, err
 	}
 
 	params := models.UpdateArticleParams{
-		Title:   req.Title,
-		Content: req.Content,
-		Status:  req.Status,
+		Title:          req.Title,
+		Content:        req.Content,
+		ContentFormat:  req.ContentFormat,
+		Status:         req.Status,
+		PublishAt:      req.PublishAt,
+		ClearPublishAt: req.ClearPublishAt,
+		EditorId:       userID,
+	}
+	// Черновик, снятый с расписания сменой статуса, не должен хранить будущую дату:
+	// при ручной публикации она сдвигается на текущий момент, в остальных случаях обнуляется
+	if article.PublishAt != nil && article.Status == models.StatusDraft && status != models.StatusDraft {
+		if status == models.StatusPublished {
+			now := time.Now()
+			params.PublishAt = &now
+		} else {
+			params.ClearPublishAt = true
+		}
+	}
+	if req.Content != nil || req.ContentFormat != nil {
+		format, content := article.ContentFormat, article.Content
+		if req.ContentFormat != nil {
+			format = *req.ContentFormat
+		}
+		if req.Content != nil {
+			content = *req.Content
+		}
+
+		contentHTML, err := renderContent(format, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render content: %w", err)
+		}
+		params.ContentHTML = &contentHTML
+	}
+	if req.Title != nil && *req.Title != article.Title {
+		slug := slugify(*req.Title)
+		params.Slug = &slug
+	}
+	if req.Tags != nil {
+		tags := normalizeTags(*req.Tags)
+		params.Tags = &tags
 	}
 
 	updatedArticle, err := a.repo.UpdateArticle(ctx, articleId, params)
@@ -128,11 +299,10 @@ func (a *ArticlesService) UpdateArticle(ctx context.Context,
 		return nil, fmt.Errorf("failed to update article: %w", err)
 	}
 
-	if err = a.cache.InvalidateLatestArticles(ctx); err != nil {
-		a.log.Error("Failed to invalidate cache", "error", err)
-	}
+	a.invalidateCache(ctx, updatedArticle.AuthorId)
 
 	response := dto.FromArticleModel(updatedArticle)
+	withArticleAuthors(ctx, a.log, a.authors, &response)
 	return &response, nil
 }
 
@@ -155,3 +325,57 @@ func (a *ArticlesService) GetLatestArticles(ctx context.Context, limit int) ([]*
 
 	return articles, nil
 }
+
+func (a *ArticlesService) ListTags(ctx context.Context) (*dto.TagsResponse, error) {
+	tags, err := a.repo.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListTags: %w", err)
+	}
+
+	response := dto.FromTagModels(tags)
+	return &response, nil
+}
+
+// invalidateCache сбрасывает кеши, в которые могла попасть статья автора
+func (a *ArticlesService) invalidateCache(ctx context.Context, authorId string) {
+	if err := a.cache.InvalidateLatestArticles(ctx); err != nil {
+		a.log.Error("Failed to invalidate cache", "error", err)
+	}
+	if err := a.cache.InvalidateFeeds(ctx, authorId); err != nil {
+		a.log.Error("Failed to invalidate feeds cache", "error", err)
+	}
+}
+
+// validateSchedule проверяет, что отложенная публикация задаётся только черновику и на будущее время
+func validateSchedule(publishAt *time.Time, status string) error {
+	if publishAt == nil {
+		return nil
+	}
+	if status != models.StatusDraft {
+		return fmt.Errorf("%w: only drafts can be scheduled", models.ErrInvalidSchedule)
+	}
+	if !publishAt.After(time.Now()) {
+		return fmt.Errorf("%w: publish_at must be in the future", models.ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// normalizeTags приводит теги к нижнему регистру и убирает дубликаты, сохраняя порядок
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"go.uber.org/zap"
+)
+
+type AuthorsProvider interface {
+	BatchGetUsers(ctx context.Context, ids []string) (map[string]*models.Author, error)
+}
+
+// loadAuthors получает авторов одним запросом к auth. Ошибка не мешает ответу:
+// клиент получит статьи и комментарии только с author_id
+func loadAuthors(ctx context.Context, log *zap.SugaredLogger, provider AuthorsProvider, ids []string) map[string]*models.Author {
+	if provider == nil || len(ids) == 0 {
+		return nil
+	}
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	authors, err := provider.BatchGetUsers(ctx, unique)
+	if err != nil {
+		log.Warnw("failed to load authors", "count", len(unique), "error", err)
+		return nil
+	}
+	return authors
+}
+
+// withArticleAuthors дополняет ответы именами авторов
+func withArticleAuthors(ctx context.Context, log *zap.SugaredLogger, provider AuthorsProvider, articles ...*dto.ArticleResponse) {
+	ids := make([]string, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.AuthorID)
+	}
+
+	authors := loadAuthors(ctx, log, provider, ids)
+	for _, article := range articles {
+		article.Author = dto.FromAuthorModel(authors[article.AuthorID])
+	}
+}
+
+// withCommentAuthors дополняет комментарии и ответы на них именами авторов
+func withCommentAuthors(ctx context.Context, log *zap.SugaredLogger, provider AuthorsProvider, comments []dto.CommentResponse) {
+	var ids []string
+	for _, comment := range comments {
+		ids = append(ids, comment.AuthorID)
+		for _, reply := range comment.Replies {
+			ids = append(ids, reply.AuthorID)
+		}
+	}
+
+	authors := loadAuthors(ctx, log, provider, ids)
+	for i := range comments {
+		comments[i].Author = dto.FromAuthorModel(authors[comments[i].AuthorID])
+		for j := range comments[i].Replies {
+			reply := &comments[i].Replies[j]
+			reply.Author = dto.FromAuthorModel(authors[reply.AuthorID])
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+	"go.uber.org/zap"
+)
+
+type CommentsRepo interface {
+	CreateComment(ctx context.Context, params models.CreateCommentParams) (*models.Comment, error)
+	GetCommentById(ctx context.Context, id string) (*models.Comment, error)
+	ListComments(ctx context.Context, params models.ListCommentsParams) ([]*models.Comment, error)
+	DeleteComment(ctx context.Context, id string) error
+}
+
+type ArticleProvider interface {
+	GetArticleById(ctx context.Context, id string) (*models.Article, error)
+}
+
+type CommentsService struct {
+	log         *zap.SugaredLogger
+	repo        CommentsRepo
+	articles    ArticleProvider
+	authors     AuthorsProvider
+	permissions PermissionChecker
+}
+
+func NewCommentsService(log *zap.SugaredLogger,
+	repo CommentsRepo,
+	articles ArticleProvider,
+	authors AuthorsProvider,
+	permissions PermissionChecker,
+) *CommentsService {
+	return &CommentsService{
+		log:         log,
+		repo:        repo,
+		articles:    articles,
+		authors:     authors,
+		permissions: permissions,
+	}
+}
+
+func (c *CommentsService) CreateComment(ctx context.Context,
+	articleId string,
+	req dto.CreateCommentRequest,
+	userID string,
+) (*dto.CommentResponse, error) {
+	article, err := c.articles.GetArticleById(ctx, articleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+
+	if article.Status != models.StatusPublished {
+		return nil, models.ErrArticleNotPublished
+	}
+
+	// Допускается только один уровень вложенности: отвечать можно лишь на корневой комментарий
+	if req.ParentID != nil {
+		parent, err := c.repo.GetCommentById(ctx, *req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parent.ArticleId != articleId || parent.ParentId != nil {
+			return nil, models.ErrInvalidParent
+		}
+	}
+
+	params := models.CreateCommentParams{
+		ArticleId: articleId,
+		ParentId:  req.ParentID,
+		AuthorId:  userID,
+		Content:   req.Content,
+	}
+
+	comment, err := c.repo.CreateComment(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed CreateComment: %w", err)
+	}
+
+	resp := []dto.CommentResponse{dto.FromCommentModel(comment)}
+	withCommentAuthors(ctx, c.log, c.authors, resp)
+	return &resp[0], nil
+}
+
+func (c *CommentsService) ListComments(ctx context.Context,
+	articleId string,
+	req dto.ListCommentsRequest,
+) (*dto.CommentsResponse, error) {
+	if _, err := c.articles.GetArticleById(ctx, articleId); err != nil {
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+
+	params := models.ListCommentsParams{
+		ArticleId: articleId,
+		Limit:     req.Limit + 1,
+	}
+	if req.Cursor != "" {
+		cursor, err := dto.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		params.After = cursor
+	}
+
+	comments, err := c.repo.ListComments(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListComments: %w", err)
+	}
+
+	var nextCursor string
+	if len(comments) > req.Limit {
+		comments = comments[:req.Limit]
+		last := comments[len(comments)-1]
+		nextCursor = dto.EncodeCursor(models.Cursor{CreatedAt: last.CreatedAt, Id: last.Id})
+	}
+
+	response := dto.FromCommentModels(comments, nextCursor)
+	withCommentAuthors(ctx, c.log, c.authors, response.Comments)
+	return &response, nil
+}
+
+func (c *CommentsService) DeleteComment(ctx context.Context, articleId, commentId, userID, role string) error {
+	comment, err := c.repo.GetCommentById(ctx, commentId)
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if comment.ArticleId != articleId {
+		return models.ErrCommentNotFound
+	}
+
+	if comment.AuthorId != userID {
+		allowed, err := canOverride(ctx, c.permissions, userID, role, authz.CommentsDeleteAny)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("%w: only author or admin can delete comment", models.ErrForbidden)
+		}
+	}
+
+	if err = c.repo.DeleteComment(ctx, commentId); err != nil {
+		return fmt.Errorf("failed DeleteComment: %w", err)
+	}
+	return nil
+}
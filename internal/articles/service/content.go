@@ -0,0 +1,22 @@
+package service
+
+import (
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/markup"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+)
+
+// renderContent строит безопасный HTML из исходника статьи; HTML хранится рядом с исходником,
+// чтобы не рендерить его при каждом чтении
+func renderContent(format, content string) (string, error) {
+	switch format {
+	case models.ContentFormatMarkdown:
+		return markup.Sanitize(markup.Markdown(content))
+	case models.ContentFormatHTML:
+		return markup.Sanitize(content)
+	case models.ContentFormatPlain:
+		return markup.Plain(content), nil
+	default:
+		return "", fmt.Errorf("unknown content format %q", format)
+	}
+}
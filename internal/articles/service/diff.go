@@ -0,0 +1,82 @@
+package service
+
+import (
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"strings"
+)
+
+// maxDiffCells ограничивает размер таблицы LCS (около 16 МБ), чтобы сравнение
+// больших ревизий не съедало память и процессор
+const maxDiffCells = 2_000_000
+
+// diffLines строит построчный diff на основе наибольшей общей подпоследовательности.
+// Общие начало и конец отрезаются заранее, чтобы таблица LCS строилась только по изменённой части.
+// Если изменённая часть слишком велика, возвращается ErrDiffTooLarge.
+func diffLines(from, to string) ([]dto.DiffLine, error) {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]dto.DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffOpEqual, Text: line})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	if (len(midA)+1)*(len(midB)+1) > maxDiffCells {
+		return nil, models.ErrDiffTooLarge
+	}
+
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) && j < len(midB) {
+		switch {
+		case midA[i] == midB[j]:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffOpEqual, Text: midA[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffOpDelete, Text: midA[i]})
+			i++
+		default:
+			lines = append(lines, dto.DiffLine{Op: dto.DiffOpInsert, Text: midB[j]})
+			j++
+		}
+	}
+	for ; i < len(midA); i++ {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffOpDelete, Text: midA[i]})
+	}
+	for ; j < len(midB); j++ {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffOpInsert, Text: midB[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, dto.DiffLine{Op: dto.DiffOpEqual, Text: line})
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/feed"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type FeedArticlesRepo interface {
+	ListArticles(ctx context.Context, params models.ListArticleParams) ([]*models.Article, error)
+}
+
+type FeedCache interface {
+	GetFeed(ctx context.Context, format, authorId string) ([]byte, error)
+	SetFeed(ctx context.Context, format, authorId string, body []byte) error
+}
+
+type FeedConfig struct {
+	BaseURL     string
+	Title       string
+	Description string
+	Limit       int
+}
+
+// FeedService отдаёт RSS/Atom ленты опубликованных статей
+type FeedService struct {
+	log   *zap.SugaredLogger
+	repo  FeedArticlesRepo
+	cache FeedCache
+	cfg   FeedConfig
+}
+
+func NewFeedService(log *zap.SugaredLogger, repo FeedArticlesRepo, cache FeedCache, cfg FeedConfig) *FeedService {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &FeedService{
+		log:   log,
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+	}
+}
+
+// GetFeed возвращает ленту в формате format; пустой authorId означает общую ленту
+func (f *FeedService) GetFeed(ctx context.Context, format, authorId string) ([]byte, error) {
+	body, err := f.cache.GetFeed(ctx, format, authorId)
+	if err == nil {
+		return body, nil
+	}
+
+	status := models.StatusPublished
+	params := models.ListArticleParams{
+		Status: &status,
+		Limit:  f.cfg.Limit,
+	}
+	if authorId != "" {
+		params.AuthorId = &authorId
+	}
+
+	articles, err := f.repo.ListArticles(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListArticles: %w", err)
+	}
+
+	meta := feed.Meta{
+		Title:       f.cfg.Title,
+		Description: f.cfg.Description,
+		Link:        f.cfg.BaseURL + "/api/v1/articles",
+		SelfLink:    f.feedLink(format, authorId),
+	}
+	if authorId != "" {
+		meta.Title = fmt.Sprintf("%s: %s", f.cfg.Title, authorId)
+		meta.Link = f.cfg.BaseURL + "/api/v1/articles?author_id=" + authorId
+	}
+
+	items := make([]feed.Item, 0, len(articles))
+	for _, article := range articles {
+		items = append(items, f.feedItem(article))
+	}
+
+	switch format {
+	case feed.FormatAtom:
+		body, err = feed.Atom(meta, items)
+	default:
+		body, err = feed.RSS(meta, items)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.cache.SetFeed(ctx, format, authorId, body); err != nil {
+		f.log.Errorw("Failed to cache feed", "format", format, "author_id", authorId, "error", err)
+	}
+
+	return body, nil
+}
+
+func (f *FeedService) feedLink(format, authorId string) string {
+	if authorId == "" {
+		return fmt.Sprintf("%s/feed.%s", f.cfg.BaseURL, format)
+	}
+	return fmt.Sprintf("%s/authors/%s/feed.%s", f.cfg.BaseURL, authorId, format)
+}
+
+func (f *FeedService) feedItem(article *models.Article) feed.Item {
+	// Отложенные статьи публикуются в publish_at, остальные - в момент создания
+	published := article.CreatedAt
+	if article.PublishAt != nil {
+		published = *article.PublishAt
+	}
+
+	return feed.Item{
+		ID:        article.Id,
+		Title:     article.Title,
+		Link:      f.cfg.BaseURL + "/api/v1/articles/by-slug/" + article.Slug,
+		Author:    article.AuthorId,
+		Content:   article.ContentHTML,
+		HTML:      true,
+		Published: published,
+		Updated:   article.UpdatedAt,
+	}
+}
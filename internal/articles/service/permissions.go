@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+)
+
+type PermissionChecker interface {
+	CheckPermission(ctx context.Context, userID, permission string) (bool, error)
+}
+
+// canOverride проверяет право действовать с чужими статьями и комментариями. Роль из токена
+// может устареть (например, у снятого администратора), поэтому право подтверждается в auth
+// по текущей роли. Роль запроса проверяется первой: у API ключей она ограничена ролью user.
+func canOverride(ctx context.Context, checker PermissionChecker, userID, role string, permission authz.Permission) (bool, error) {
+	if !authz.Can(role, permission) {
+		return false, nil
+	}
+
+	allowed, err := checker.CheckPermission(ctx, userID, string(permission))
+	if err != nil {
+		return false, fmt.Errorf("failed CheckPermission: %w", err)
+	}
+	return allowed, nil
+}
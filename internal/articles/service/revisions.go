@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+)
+
+func (a *ArticlesService) ListRevisions(ctx context.Context, articleId string) (*dto.RevisionsResponse, error) {
+	if _, err := a.repo.GetArticleById(ctx, articleId); err != nil {
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+
+	revisions, err := a.repo.ListRevisions(ctx, articleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListRevisions: %w", err)
+	}
+
+	response := dto.FromRevisionModels(articleId, revisions)
+	return &response, nil
+}
+
+func (a *ArticlesService) GetRevision(ctx context.Context, articleId string, revisionNum int) (*dto.RevisionResponse, error) {
+	revision, err := a.repo.GetRevision(ctx, articleId, revisionNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetRevision: %w", err)
+	}
+
+	response := dto.FromRevisionModel(revision)
+	return &response, nil
+}
+
+func (a *ArticlesService) DiffRevisions(ctx context.Context, articleId string, from, to int) (*dto.RevisionDiffResponse, error) {
+	fromRevision, err := a.repo.GetRevision(ctx, articleId, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d: %w", from, err)
+	}
+
+	toRevision, err := a.repo.GetRevision(ctx, articleId, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d: %w", to, err)
+	}
+
+	lines, err := diffLines(fromRevision.Content, toRevision.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff revisions %d and %d: %w", from, to, err)
+	}
+
+	return &dto.RevisionDiffResponse{
+		ArticleID: articleId,
+		From:      from,
+		To:        to,
+		TitleFrom: fromRevision.Title,
+		TitleTo:   toRevision.Title,
+		Lines:     lines,
+	}, nil
+}
+
+// RestoreRevision откатывает статью к ревизии через обычное обновление,
+// поэтому проверка авторства и запись новой ревизии происходят там же
+func (a *ArticlesService) RestoreRevision(ctx context.Context,
+	articleId string,
+	revisionNum int,
+	userID, role string,
+) (*dto.ArticleResponse, error) {
+	revision, err := a.repo.GetRevision(ctx, articleId, revisionNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetRevision: %w", err)
+	}
+
+	req := dto.UpdateRequest{
+		Title:   &revision.Title,
+		Content: &revision.Content,
+	}
+
+	return a.UpdateArticle(ctx, articleId, req, userID, role)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ScheduledArticlesRepo interface {
+	PublishDueArticles(ctx context.Context, now time.Time, limit int) ([]*models.Article, error)
+}
+
+// PublishScheduler периодически публикует черновики с наступившим publish_at
+type PublishScheduler struct {
+	log       *zap.SugaredLogger
+	repo      ScheduledArticlesRepo
+	cache     ArticlesCache
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPublishScheduler(
+	log *zap.SugaredLogger,
+	repo ScheduledArticlesRepo,
+	cache ArticlesCache,
+	interval time.Duration,
+	batchSize int,
+) *PublishScheduler {
+	return &PublishScheduler{
+		log:       log,
+		repo:      repo,
+		cache:     cache,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run блокируется до отмены ctx
+func (s *PublishScheduler) Run(ctx context.Context) {
+	s.log.Infow("Starting publish scheduler", "interval", s.interval, "batch_size", s.batchSize)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.log.Info("Publish scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *PublishScheduler) publishDue(ctx context.Context) {
+	for {
+		articles, err := s.repo.PublishDueArticles(ctx, time.Now(), s.batchSize)
+		if err != nil {
+			s.log.Errorw("Failed to publish scheduled articles", "error", err)
+			return
+		}
+		if len(articles) == 0 {
+			return
+		}
+
+		for _, article := range articles {
+			s.log.Infow("Scheduled article published", "id", article.Id, "author_id", article.AuthorId)
+
+			if err = s.cache.InvalidateFeeds(ctx, article.AuthorId); err != nil {
+				s.log.Error("Failed to invalidate feeds cache", "error", err)
+			}
+		}
+
+		if err = s.cache.InvalidateLatestArticles(ctx); err != nil {
+			s.log.Error("Failed to invalidate cache", "error", err)
+		}
+
+		if len(articles) < s.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import "strings"
+
+const (
+	maxSlugLength = 80
+	defaultSlug   = "article"
+)
+
+// cyrillicTranslit - упрощённая транслитерация в духе ГОСТ 7.79-2000 (система Б) для русского и украинского алфавитов
+var cyrillicTranslit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// slugify строит slug из заголовка: латиница и цифры сохраняются, кириллица транслитерируется,
+// остальные символы становятся разделителями
+func slugify(title string) string {
+	var b strings.Builder
+	pendingDash := false
+
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		default:
+			translit, ok := cyrillicTranslit[r]
+			if !ok {
+				pendingDash = true
+				continue
+			}
+			part = translit
+		}
+
+		if part == "" {
+			continue
+		}
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndexByte(slug, '-'); i > maxSlugLength/2 {
+			slug = slug[:i]
+		}
+		slug = strings.Trim(slug, "-")
+	}
+
+	if slug == "" {
+		return defaultSlug
+	}
+	return slug
+}
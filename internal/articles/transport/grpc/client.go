@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/retry"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
 	authv1 "github.com/mSulimenko/dev-blog-platform/protos/gen/go"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -13,6 +14,9 @@ import (
 	"time"
 )
 
+// batchUsersLimit совпадает с ограничением BatchGetUsers на стороне auth
+const batchUsersLimit = 100
+
 type Client struct {
 	api authv1.AuthClient
 	log *zap.SugaredLogger
@@ -62,3 +66,71 @@ func (c *Client) Validate(ctx context.Context, token string) (*dto.ValidationRes
 	return &resp, nil
 
 }
+
+func (c *Client) ValidateAPIKey(ctx context.Context, key string) (*dto.ValidationResp, error) {
+	grpcResp, err := c.api.Validate(ctx, &authv1.ValidateRequest{ApiKey: key})
+	if err != nil {
+		c.log.Errorw("failed to validate api key", "error", err)
+		return nil, fmt.Errorf("failed validation api key: %w", err)
+	}
+
+	resp := dto.ValidationResp{
+		Valid:  grpcResp.Valid,
+		UserId: grpcResp.UserId,
+		Role:   grpcResp.Role,
+		Scopes: grpcResp.Scopes,
+	}
+	// Пустой набор scopes у ключа не даёт прав, поэтому он не должен превращаться в nil
+	if resp.Scopes == nil {
+		resp.Scopes = []string{}
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) SigningKeys(ctx context.Context) ([]*authv1.SigningKey, error) {
+	grpcResp, err := c.api.GetSigningKeys(ctx, &authv1.GetSigningKeysRequest{})
+	if err != nil {
+		c.log.Errorw("failed to get signing keys", "error", err)
+		return nil, fmt.Errorf("failed getting signing keys: %w", err)
+	}
+
+	return grpcResp.Keys, nil
+}
+
+// BatchGetUsers возвращает авторов по id; неизвестные id в результат не попадают
+func (c *Client) BatchGetUsers(ctx context.Context, ids []string) (map[string]*models.Author, error) {
+	authors := make(map[string]*models.Author, len(ids))
+	for start := 0; start < len(ids); start += batchUsersLimit {
+		end := min(start+batchUsersLimit, len(ids))
+
+		grpcResp, err := c.api.BatchGetUsers(ctx, &authv1.BatchGetUsersRequest{UserIds: ids[start:end]})
+		if err != nil {
+			c.log.Errorw("failed to batch get users", "count", end-start, "error", err)
+			return nil, fmt.Errorf("failed batch getting users: %w", err)
+		}
+
+		for _, user := range grpcResp.Users {
+			authors[user.Id] = toAuthor(user)
+		}
+	}
+
+	return authors, nil
+}
+
+func (c *Client) CheckPermission(ctx context.Context, userID, permission string) (bool, error) {
+	grpcResp, err := c.api.CheckPermission(ctx, &authv1.CheckPermissionRequest{UserId: userID, Permission: permission})
+	if err != nil {
+		c.log.Errorw("failed to check permission", "userID", userID, "permission", permission, "error", err)
+		return false, fmt.Errorf("failed checking permission: %w", err)
+	}
+
+	return grpcResp.Allowed, nil
+}
+
+func toAuthor(user *authv1.User) *models.Author {
+	return &models.Author{
+		Id:       user.GetId(),
+		Username: user.GetUsername(),
+	}
+}
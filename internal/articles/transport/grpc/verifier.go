@@ -0,0 +1,272 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// minKeysRefresh ограничивает обновление ключей при токенах с неизвестным kid
+const minKeysRefresh = 10 * time.Second
+
+type VerifierConfig struct {
+	RevocationCheck     bool
+	CacheTTL            time.Duration
+	KeysRefreshInterval time.Duration
+}
+
+type verifyKey struct {
+	alg string
+	key any
+}
+
+type cachedResult struct {
+	resp      *dto.ValidationResp
+	expiresAt time.Time
+}
+
+// Verifier проверяет подпись и срок действия токенов локально по публичным ключам auth-сервиса.
+// Validate вызывается, только если ключ неизвестен (в том числе токены HS256 без kid),
+// в токене нет роли или включена проверка отзыва. Результаты кешируются на CacheTTL;
+// при проверке отзыва успешные результаты не кешируются, чтобы отзыв действовал сразу.
+type Verifier struct {
+	client *Client
+	log    *zap.SugaredLogger
+	cfg    VerifierConfig
+
+	keysMu      sync.RWMutex
+	keys        map[string]verifyKey
+	keysFetched time.Time
+	keysTried   time.Time
+	keysRefresh singleflight.Group
+
+	resultsMu sync.Mutex
+	results   map[string]cachedResult
+	lastSweep time.Time
+}
+
+func NewVerifier(client *Client, log *zap.SugaredLogger, cfg VerifierConfig) *Verifier {
+	return &Verifier{
+		client:  client,
+		log:     log,
+		cfg:     cfg,
+		keys:    make(map[string]verifyKey),
+		results: make(map[string]cachedResult),
+	}
+}
+
+// ValidateAPIKey проверяет API ключ через auth-сервис; результат кешируется так же, как для токенов
+func (v *Verifier) ValidateAPIKey(ctx context.Context, key string) (*dto.ValidationResp, error) {
+	sum := sha256.Sum256([]byte(key))
+	cacheKey := "apikey:" + hex.EncodeToString(sum[:])
+
+	if resp, ok := v.cached(cacheKey); ok {
+		return resp, nil
+	}
+
+	resp, err := v.client.ValidateAPIKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	v.store(cacheKey, resp, time.Now().Add(v.cfg.CacheTTL))
+	return resp, nil
+}
+
+func (v *Verifier) Validate(ctx context.Context, token string) (*dto.ValidationResp, error) {
+	sum := sha256.Sum256([]byte(token))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	if resp, ok := v.cached(cacheKey); ok {
+		return resp, nil
+	}
+
+	resp, expiresAt, err := v.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	v.store(cacheKey, resp, expiresAt)
+	return resp, nil
+}
+
+// verify возвращает результат проверки и момент, после которого его нельзя использовать
+func (v *Verifier) verify(ctx context.Context, token string) (*dto.ValidationResp, time.Time, error) {
+	parser := jwt.NewParser()
+
+	unverified, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return &dto.ValidationResp{Valid: false}, time.Now().Add(v.cfg.CacheTTL), nil
+	}
+
+	kid, _ := unverified.Header["kid"].(string)
+	if kid == "" {
+		return v.remote(ctx, token)
+	}
+
+	key, ok := v.key(ctx, kid)
+	if !ok {
+		v.log.Infow("unknown signing key, falling back to Validate", "kid", kid)
+		return v.remote(ctx, token)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return key.key, nil
+	}, jwt.WithValidMethods([]string{key.alg}), jwt.WithExpirationRequired())
+	if err != nil {
+		// Неверная подпись или истёкший срок - токен отклоняется без обращения к auth
+		return &dto.ValidationResp{Valid: false}, time.Now().Add(v.cfg.CacheTTL), nil
+	}
+
+	// Служебные токены (например, MFA challenge) не дают доступа
+	if purpose, _ := claims["purpose"].(string); purpose != "" {
+		return &dto.ValidationResp{Valid: false}, time.Now().Add(v.cfg.CacheTTL), nil
+	}
+
+	userId, _ := claims["uid"].(string)
+	role, _ := claims["role"].(string)
+	if userId == "" || role == "" {
+		// Токены, выпущенные до появления роли в claims
+		return v.remote(ctx, token)
+	}
+
+	if v.cfg.RevocationCheck {
+		return v.remote(ctx, token)
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("token expiration: %w", err)
+	}
+
+	return &dto.ValidationResp{
+		Valid:  true,
+		UserId: userId,
+		Role:   role,
+	}, exp.Time, nil
+}
+
+func (v *Verifier) remote(ctx context.Context, token string) (*dto.ValidationResp, time.Time, error) {
+	resp, err := v.client.Validate(ctx, token)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return resp, time.Now().Add(v.cfg.CacheTTL), nil
+}
+
+// key ищет ключ по kid, обновляя набор ключей, если он устарел или kid в нём нет
+func (v *Verifier) key(ctx context.Context, kid string) (verifyKey, bool) {
+	v.keysMu.RLock()
+	key, ok := v.keys[kid]
+	stale := time.Since(v.keysFetched) > v.cfg.KeysRefreshInterval
+	v.keysMu.RUnlock()
+
+	if ok && !stale {
+		return key, true
+	}
+
+	if err := v.refreshKeys(ctx); err != nil {
+		v.log.Warnw("failed to refresh signing keys", "error", err)
+	}
+
+	v.keysMu.RLock()
+	defer v.keysMu.RUnlock()
+	key, ok = v.keys[kid]
+	return key, ok
+}
+
+// refreshKeys загружает ключи без блокировки набора, чтобы токены с чужим kid не останавливали
+// остальные проверки на время RPC. Одновременные обновления схлопываются в один запрос.
+func (v *Verifier) refreshKeys(ctx context.Context) error {
+	_, err, _ := v.keysRefresh.Do("keys", func() (any, error) {
+		// Запрос выполняется для всех ожидающих, поэтому отмена одного из них не должна его прерывать
+		return nil, v.fetchKeys(context.WithoutCancel(ctx))
+	})
+	return err
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context) error {
+	v.keysMu.Lock()
+	if time.Since(v.keysTried) < minKeysRefresh {
+		v.keysMu.Unlock()
+		return nil
+	}
+	v.keysTried = time.Now()
+	v.keysMu.Unlock()
+
+	signingKeys, err := v.client.SigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]verifyKey, len(signingKeys))
+	for _, signingKey := range signingKeys {
+		public, err := x509.ParsePKIXPublicKey(signingKey.PublicKey)
+		if err != nil {
+			v.log.Warnw("skipping invalid signing key", "kid", signingKey.Kid, "error", err)
+			continue
+		}
+		keys[signingKey.Kid] = verifyKey{alg: signingKey.Algorithm, key: public}
+	}
+
+	if len(keys) == 0 && len(signingKeys) > 0 {
+		return errors.New("no usable signing keys")
+	}
+
+	v.keysMu.Lock()
+	v.keys = keys
+	v.keysFetched = time.Now()
+	v.keysMu.Unlock()
+
+	v.log.Infow("signing keys refreshed", "count", len(keys))
+	return nil
+}
+
+func (v *Verifier) cached(cacheKey string) (*dto.ValidationResp, bool) {
+	v.resultsMu.Lock()
+	defer v.resultsMu.Unlock()
+
+	result, ok := v.results[cacheKey]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(result.expiresAt) {
+		delete(v.results, cacheKey)
+		return nil, false
+	}
+	return result.resp, true
+}
+
+func (v *Verifier) store(cacheKey string, resp *dto.ValidationResp, expiresAt time.Time) {
+	if resp.Valid && v.cfg.RevocationCheck {
+		return
+	}
+	if ttl := time.Now().Add(v.cfg.CacheTTL); expiresAt.After(ttl) {
+		expiresAt = ttl
+	}
+
+	v.resultsMu.Lock()
+	defer v.resultsMu.Unlock()
+
+	// Истёкшие записи вычищаются не чаще раза в CacheTTL
+	now := time.Now()
+	if now.Sub(v.lastSweep) > v.cfg.CacheTTL {
+		for key, result := range v.results {
+			if now.After(result.expiresAt) {
+				delete(v.results, key)
+			}
+		}
+		v.lastSweep = now
+	}
+	v.results[cacheKey] = cachedResult{resp: resp, expiresAt: expiresAt}
+}
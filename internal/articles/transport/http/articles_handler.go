@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
@@ -39,7 +41,7 @@ func (h *Handler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	article, err := h.articlesService.CreateArticle(r.Context(), req)
 	if err != nil {
 		h.log.Errorw("Failed to create article", "error", err)
-		http.Error(w, `{"error": "Failed to create article"}`, http.StatusInternalServerError)
+		sendServiceError(w, err)
 		return
 	}
 
@@ -66,6 +68,29 @@ func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(article)
 }
 
+func (h *Handler) GetArticleBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	if slug == "" {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "slug required")
+		return
+	}
+
+	article, currentSlug, err := h.articlesService.GetArticleBySlug(r.Context(), slug)
+	if err != nil {
+		h.log.Errorw("Failed to get article by slug", "slug", slug, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	if currentSlug != "" {
+		http.Redirect(w, r, "/api/v1/articles/by-slug/"+url.PathEscape(currentSlug), http.StatusMovedPermanently)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(article)
+}
+
 func (h *Handler) ListArticles(w http.ResponseWriter, r *http.Request) {
 	var req dto.ListRequest
 	query := r.URL.Query()
@@ -76,20 +101,14 @@ func (h *Handler) ListArticles(w http.ResponseWriter, r *http.Request) {
 	if status := query.Get("status"); status != "" {
 		req.Status = &status
 	}
-	offsetStr := query.Get("offset")
-	if offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil {
-			req.Offset = offset
-		}
-	}
-	limitStr := query.Get("limit")
-	if limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			req.Limit = limit
-		}
-	}
-	if req.Limit == 0 {
-		req.Limit = 20
+	req.Tags = query["tag"]
+	req.TagMatch = query.Get("tag_match")
+	req.Cursor = query.Get("cursor")
+	req.Offset, req.Limit = parsePagination(query)
+
+	if req.Cursor != "" && req.Offset != 0 {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "cursor and offset cannot be combined")
+		return
 	}
 
 	if err := h.validate.Struct(req); err != nil {
@@ -101,7 +120,7 @@ func (h *Handler) ListArticles(w http.ResponseWriter, r *http.Request) {
 	articles, err := h.articlesService.ListArticles(r.Context(), req)
 	if err != nil {
 		h.log.Errorw("Failed to list articles", "error", err)
-		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "internal error")
+		sendServiceError(w, err)
 		return
 	}
 
@@ -109,6 +128,30 @@ func (h *Handler) ListArticles(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(articles)
 }
 
+func (h *Handler) SearchArticles(w http.ResponseWriter, r *http.Request) {
+	var req dto.SearchRequest
+	query := r.URL.Query()
+
+	req.Query = strings.TrimSpace(query.Get("q"))
+	req.Offset, req.Limit = parsePagination(query)
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Warnw("Validation failed for search request", "error", err)
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid parameters")
+		return
+	}
+
+	results, err := h.articlesService.SearchArticles(r.Context(), req)
+	if err != nil {
+		h.log.Errorw("Failed to search articles", "error", err)
+		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
 func (h *Handler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	articleId := chi.URLParam(r, "id")
 	if articleId == "" {
@@ -123,7 +166,7 @@ func (h *Handler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Title == nil && req.Content == nil && req.Status == nil {
+	if req.Title == nil && req.Content == nil && req.ContentFormat == nil && req.Status == nil && req.Tags == nil && req.PublishAt == nil && !req.ClearPublishAt {
 		sendError(w, http.StatusBadRequest, ErrCodeValidation, "At least one field must be provided for update")
 		return
 	}
@@ -138,11 +181,16 @@ func (h *Handler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
 		return
 	}
+	role, err := getctx.GetUserRoleFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
 
-	article, err := h.articlesService.UpdateArticle(r.Context(), articleId, req, userID)
+	article, err := h.articlesService.UpdateArticle(r.Context(), articleId, req, userID, role)
 	if err != nil {
 		h.log.Errorw("Failed to update article", "id", articleId, "error", err)
-		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal error")
+		sendServiceError(w, err)
 		return
 	}
 
@@ -161,10 +209,16 @@ func (h *Handler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
 		return
 	}
-	err = h.articlesService.DeleteArticle(r.Context(), articleId, userID)
+	role, err := getctx.GetUserRoleFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+
+	err = h.articlesService.DeleteArticle(r.Context(), articleId, userID, role)
 	if err != nil {
 		h.log.Errorw("Failed to delete article", "id", articleId, "error", err)
-		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal error")
+		sendServiceError(w, err)
 		return
 	}
 
@@ -196,3 +250,22 @@ func (h *Handler) GetLatestArticles(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// parsePagination читает offset и limit из query, некорректные значения игнорируются
+func parsePagination(query url.Values) (offset, limit int) {
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if v, err := strconv.Atoi(offsetStr); err == nil {
+			offset = v
+		}
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if v, err := strconv.Atoi(limitStr); err == nil {
+			limit = v
+		}
+	}
+	if limit == 0 {
+		limit = 20
+	}
+
+	return offset, limit
+}
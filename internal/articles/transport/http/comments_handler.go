@@ -0,0 +1,105 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+)
+
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	if articleId == "" {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id required")
+		return
+	}
+
+	var req dto.ListCommentsRequest
+	query := r.URL.Query()
+	req.Cursor = query.Get("cursor")
+	_, req.Limit = parsePagination(query)
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Warnw("Validation failed for comments request", "error", err)
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid parameters")
+		return
+	}
+
+	comments, err := h.commentsService.ListComments(r.Context(), articleId, req)
+	if err != nil {
+		h.log.Errorw("Failed to list comments", "id", articleId, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	if articleId == "" {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id required")
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("Failed to decode request body", "error", err)
+		sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Warnw("Validation failed", "error", err)
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid request")
+		return
+	}
+
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+
+	comment, err := h.commentsService.CreateComment(r.Context(), articleId, req, userID)
+	if err != nil {
+		h.log.Errorw("Failed to create comment", "id", articleId, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	commentId := chi.URLParam(r, "commentId")
+	if articleId == "" || commentId == "" {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id and commentId required")
+		return
+	}
+
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+	role, err := getctx.GetUserRoleFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+
+	err = h.commentsService.DeleteComment(r.Context(), articleId, commentId, userID, role)
+	if err != nil {
+		h.log.Errorw("Failed to delete comment", "id", commentId, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
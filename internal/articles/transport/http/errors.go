@@ -2,6 +2,8 @@ package httphandler
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
 	"net/http"
 )
 
@@ -16,6 +18,7 @@ const (
 	ErrCodeInternal    = "INTERNAL_ERROR"
 	ErrCodeConflict    = "CONFLICT"
 	ErrCodeInvalidJSON = "INVALID_JSON"
+	ErrCodeForbidden   = "FORBIDDEN"
 )
 
 func sendError(w http.ResponseWriter, status int, code, message string) {
@@ -26,3 +29,29 @@ func sendError(w http.ResponseWriter, status int, code, message string) {
 		Message: message,
 	})
 }
+
+// sendServiceError переводит доменные ошибки сервиса в HTTP-ответ
+func sendServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrArticleNotFound):
+		sendError(w, http.StatusNotFound, ErrCodeNotFound, "Article not found")
+	case errors.Is(err, models.ErrRevisionNotFound):
+		sendError(w, http.StatusNotFound, ErrCodeNotFound, "Revision not found")
+	case errors.Is(err, models.ErrCommentNotFound):
+		sendError(w, http.StatusNotFound, ErrCodeNotFound, "Comment not found")
+	case errors.Is(err, models.ErrArticleNotPublished):
+		sendError(w, http.StatusConflict, ErrCodeConflict, "Article is not published")
+	case errors.Is(err, models.ErrInvalidParent):
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "Replies are allowed only to top-level comments of the same article")
+	case errors.Is(err, models.ErrInvalidCursor):
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid cursor")
+	case errors.Is(err, models.ErrInvalidSchedule):
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, err.Error())
+	case errors.Is(err, models.ErrDiffTooLarge):
+		sendError(w, http.StatusUnprocessableEntity, ErrCodeValidation, "Revisions differ too much to compare, choose closer revisions")
+	case errors.Is(err, models.ErrForbidden):
+		sendError(w, http.StatusForbidden, ErrCodeForbidden, "Forbidden")
+	default:
+		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal error")
+	}
+}
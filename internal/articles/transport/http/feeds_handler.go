@@ -0,0 +1,47 @@
+package httphandler
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/feed"
+	"net/http"
+)
+
+func (h *Handler) GetRSSFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.FormatRSS, "")
+}
+
+func (h *Handler) GetAtomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.FormatAtom, "")
+}
+
+func (h *Handler) GetAuthorRSSFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.FormatRSS, chi.URLParam(r, "id"))
+}
+
+func (h *Handler) GetAuthorAtomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, feed.FormatAtom, chi.URLParam(r, "id"))
+}
+
+func (h *Handler) serveFeed(w http.ResponseWriter, r *http.Request, format, authorId string) {
+	if authorId != "" {
+		if err := h.validate.Var(authorId, "uuid"); err != nil {
+			sendError(w, http.StatusBadRequest, ErrCodeValidation, "invalid author id")
+			return
+		}
+	}
+
+	body, err := h.feedService.GetFeed(r.Context(), format, authorId)
+	if err != nil {
+		h.log.Errorw("Failed to build feed", "format", format, "author_id", authorId, "error", err)
+		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "internal error")
+		return
+	}
+
+	contentType := feed.ContentTypeRSS
+	if format == feed.FormatAtom {
+		contentType = feed.ContentTypeAtom
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
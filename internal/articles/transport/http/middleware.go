@@ -2,12 +2,17 @@ package httphandler
 
 import (
 	"context"
-	"github.com/mSulimenko/dev-blog-platform/internal/articles/transport/grpc"
+	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
 	"net/http"
 	"strings"
 )
 
-func (h *Handler) AuthMiddleware(grpcAuthClient *grpc.Client) func(http.Handler) http.Handler {
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (*dto.ValidationResp, error)
+	ValidateAPIKey(ctx context.Context, key string) (*dto.ValidationResp, error)
+}
+
+func (h *Handler) AuthMiddleware(tokenValidator TokenValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -17,14 +22,21 @@ func (h *Handler) AuthMiddleware(grpcAuthClient *grpc.Client) func(http.Handler)
 			}
 
 			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
+			if len(parts) != 2 || (parts[0] != "Bearer" && parts[0] != "ApiKey") {
 				sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Invalid authorization header format")
 				return
 			}
 
-			token := parts[1]
-
-			validationResp, err := grpcAuthClient.Validate(r.Context(), token)
+			var (
+				validationResp *dto.ValidationResp
+				err            error
+			)
+			// Authorization: ApiKey <key> - персональный ключ для скриптов и CI
+			if parts[0] == "ApiKey" {
+				validationResp, err = tokenValidator.ValidateAPIKey(r.Context(), parts[1])
+			} else {
+				validationResp, err = tokenValidator.Validate(r.Context(), parts[1])
+			}
 			if err != nil {
 				h.log.Errorw("Token validation failed", "error", err)
 				sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Invalid token")
@@ -38,6 +50,9 @@ func (h *Handler) AuthMiddleware(grpcAuthClient *grpc.Client) func(http.Handler)
 
 			ctx := context.WithValue(r.Context(), "user_id", validationResp.UserId)
 			ctx = context.WithValue(ctx, "user_role", validationResp.Role)
+			if validationResp.Scopes != nil {
+				ctx = context.WithValue(ctx, "user_scopes", validationResp.Scopes)
+			}
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -0,0 +1,98 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	if articleId == "" {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id required")
+		return
+	}
+
+	revisions, err := h.articlesService.ListRevisions(r.Context(), articleId)
+	if err != nil {
+		h.log.Errorw("Failed to list revisions", "id", articleId, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+func (h *Handler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	revisionNum, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if articleId == "" || err != nil || revisionNum < 1 {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id and valid revision required")
+		return
+	}
+
+	revision, err := h.articlesService.GetRevision(r.Context(), articleId, revisionNum)
+	if err != nil {
+		h.log.Errorw("Failed to get revision", "id", articleId, "revision", revisionNum, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revision)
+}
+
+func (h *Handler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	query := r.URL.Query()
+
+	from, fromErr := strconv.Atoi(query.Get("from"))
+	to, toErr := strconv.Atoi(query.Get("to"))
+	if articleId == "" || fromErr != nil || toErr != nil || from < 1 || to < 1 {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "from and to revisions required")
+		return
+	}
+
+	diff, err := h.articlesService.DiffRevisions(r.Context(), articleId, from, to)
+	if err != nil {
+		h.log.Errorw("Failed to diff revisions", "id", articleId, "from", from, "to", to, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+func (h *Handler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	articleId := chi.URLParam(r, "id")
+	revisionNum, err := strconv.Atoi(chi.URLParam(r, "revision"))
+	if articleId == "" || err != nil || revisionNum < 1 {
+		sendError(w, http.StatusBadRequest, ErrCodeValidation, "id and valid revision required")
+		return
+	}
+
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+	role, err := getctx.GetUserRoleFromContext(r.Context())
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, ErrCodeValidation, "Authentication required")
+		return
+	}
+
+	article, err := h.articlesService.RestoreRevision(r.Context(), articleId, revisionNum, userID, role)
+	if err != nil {
+		h.log.Errorw("Failed to restore revision", "id", articleId, "revision", revisionNum, "error", err)
+		sendServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(article)
+}
@@ -7,30 +7,57 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/dto"
 	"github.com/mSulimenko/dev-blog-platform/internal/articles/models"
-	"github.com/mSulimenko/dev-blog-platform/internal/articles/transport/grpc"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
 	"go.uber.org/zap"
 )
 
 type ArticlesServiceInterface interface {
 	CreateArticle(ctx context.Context, req dto.CreateRequest) (*dto.ArticleResponse, error)
 	GetArticle(ctx context.Context, id string) (*dto.ArticleResponse, error)
-	DeleteArticle(ctx context.Context, articleId string, userID string) error
+	GetArticleBySlug(ctx context.Context, slug string) (*dto.ArticleResponse, string, error)
+	DeleteArticle(ctx context.Context, articleId, userID, role string) error
 	ListArticles(ctx context.Context, req dto.ListRequest) (*dto.ListResponse, error)
-	UpdateArticle(ctx context.Context, articleId string, req dto.UpdateRequest, userID string) (*dto.ArticleResponse, error)
+	SearchArticles(ctx context.Context, req dto.SearchRequest) (*dto.SearchResponse, error)
+	UpdateArticle(ctx context.Context, articleId string, req dto.UpdateRequest, userID, role string) (*dto.ArticleResponse, error)
 	GetLatestArticles(ctx context.Context, limit int) ([]*models.Article, error)
+	ListTags(ctx context.Context) (*dto.TagsResponse, error)
+	ListRevisions(ctx context.Context, articleId string) (*dto.RevisionsResponse, error)
+	GetRevision(ctx context.Context, articleId string, revisionNum int) (*dto.RevisionResponse, error)
+	DiffRevisions(ctx context.Context, articleId string, from, to int) (*dto.RevisionDiffResponse, error)
+	RestoreRevision(ctx context.Context, articleId string, revisionNum int, userID, role string) (*dto.ArticleResponse, error)
+}
+
+type CommentsServiceInterface interface {
+	CreateComment(ctx context.Context, articleId string, req dto.CreateCommentRequest, userID string) (*dto.CommentResponse, error)
+	ListComments(ctx context.Context, articleId string, req dto.ListCommentsRequest) (*dto.CommentsResponse, error)
+	DeleteComment(ctx context.Context, articleId, commentId, userID, role string) error
+}
+
+type FeedServiceInterface interface {
+	GetFeed(ctx context.Context, format, authorId string) ([]byte, error)
 }
 
 type Handler struct {
 	articlesService ArticlesServiceInterface
+	commentsService CommentsServiceInterface
+	feedService     FeedServiceInterface
 	log             *zap.SugaredLogger
 	validate        *validator.Validate
-	authClient      *grpc.Client
+	authClient      TokenValidator
 }
 
-func NewHandler(articlesService ArticlesServiceInterface, logger *zap.SugaredLogger, authClient *grpc.Client) *Handler {
+func NewHandler(
+	articlesService ArticlesServiceInterface,
+	commentsService CommentsServiceInterface,
+	feedService FeedServiceInterface,
+	logger *zap.SugaredLogger,
+	authClient TokenValidator,
+) *Handler {
 	validate := validator.New()
 	return &Handler{
 		articlesService: articlesService,
+		commentsService: commentsService,
+		feedService:     feedService,
 		log:             logger,
 		validate:        validate,
 		authClient:      authClient,
@@ -45,22 +72,47 @@ func (h *Handler) InitRouter() *chi.Mux {
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 
+	router.Get("/feed.rss", h.GetRSSFeed)   // GET /feed.rss
+	router.Get("/feed.atom", h.GetAtomFeed) // GET /feed.atom
+	router.Route("/authors/{id}", func(r chi.Router) {
+		r.Get("/feed.rss", h.GetAuthorRSSFeed)   // GET /authors/{id}/feed.rss
+		r.Get("/feed.atom", h.GetAuthorAtomFeed) // GET /authors/{id}/feed.atom
+	})
+
 	router.Route("/api/v1", func(r chi.Router) {
 		r.Route("/articles", func(r chi.Router) {
 			// Публичные endpoints
 			r.Get("/", h.ListArticles)   // GET /api/v1/articles
 			r.Get("/{id}", h.GetArticle) // GET /api/v1/articles/{id}
 			r.Get("/latest", h.GetLatestArticles)
+			r.Get("/by-slug/{slug}", h.GetArticleBySlug) // GET /api/v1/articles/by-slug/{slug}
+			r.Get("/search", h.SearchArticles)           // GET /api/v1/articles/search?q=
+
+			r.Get("/{id}/revisions", h.ListRevisions)          // GET /api/v1/articles/{id}/revisions
+			r.Get("/{id}/revisions/diff", h.DiffRevisions)     // GET /api/v1/articles/{id}/revisions/diff?from=&to=
+			r.Get("/{id}/revisions/{revision}", h.GetRevision) // GET /api/v1/articles/{id}/revisions/{revision}
+
+			r.Get("/{id}/comments", h.ListComments) // GET /api/v1/articles/{id}/comments
 
 			// Защищенные endpoints
 			r.Group(func(r chi.Router) {
 				r.Use(h.AuthMiddleware(h.authClient))
 
-				r.Post("/", h.CreateArticle)       // POST /api/v1/articles
-				r.Put("/{id}", h.UpdateArticle)    // PUT /api/v1/articles/{id}
-				r.Delete("/{id}", h.DeleteArticle) // DELETE /api/v1/articles/{id}
+				r.With(authz.Require(authz.ArticlesCreate)).Post("/", h.CreateArticle) // POST /api/v1/articles
+
+				// Каждый изменяющий маршрут объявляет право: по нему проверяются и роль, и scopes API ключа.
+				// Правка и удаление чужих статей дополнительно проверяются в сервисе по правам роли
+				r.With(authz.Require(authz.ArticlesUpdate)).Put("/{id}", h.UpdateArticle)    // PUT /api/v1/articles/{id}
+				r.With(authz.Require(authz.ArticlesDelete)).Delete("/{id}", h.DeleteArticle) // DELETE /api/v1/articles/{id}
+
+				r.With(authz.Require(authz.ArticlesRestore)).Post("/{id}/revisions/{revision}/restore", h.RestoreRevision) // POST /api/v1/articles/{id}/revisions/{revision}/restore
+
+				r.With(authz.Require(authz.CommentsCreate)).Post("/{id}/comments", h.CreateComment)               // POST /api/v1/articles/{id}/comments
+				r.With(authz.Require(authz.CommentsDelete)).Delete("/{id}/comments/{commentId}", h.DeleteComment) // DELETE /api/v1/articles/{id}/comments/{commentId}
 			})
 		})
+
+		r.Get("/tags", h.ListTags) // GET /api/v1/tags
 	})
 
 	return router
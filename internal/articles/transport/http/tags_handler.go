@@ -0,0 +1,18 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.articlesService.ListTags(r.Context())
+	if err != nil {
+		h.log.Errorw("Failed to list tags", "error", err)
+		sendError(w, http.StatusInternalServerError, ErrCodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
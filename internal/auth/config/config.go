@@ -26,6 +26,9 @@ type HTTP struct {
 	ReadTimeout  time.Duration `yaml:"read_timeout"`
 	WriteTimeout time.Duration `yaml:"write_timeout"`
 	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// Адреса или подсети прокси, которым доверяются X-Forwarded-For и X-Real-IP.
+	// Пустой список - адрес клиента берётся только из соединения
+	TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 }
 
 type DB struct {
@@ -37,8 +40,67 @@ type DB struct {
 }
 
 type Auth struct {
-	AccessSecret   string        `yaml:"access_secret" env:"env-required"`
-	AccessDuration time.Duration `yaml:"access_duration" envDefault:"15m"`
+	AccessSecret        string        `yaml:"access_secret" env:"env-required"`
+	AccessDuration      time.Duration `yaml:"access_duration" envDefault:"15m"`
+	RefreshDuration     time.Duration `yaml:"refresh_duration" env-default:"720h"`
+	ResetDuration       time.Duration `yaml:"reset_duration" env-default:"1h"`
+	EmailChangeDuration time.Duration `yaml:"email_change_duration" env-default:"24h"`
+	VerifyDuration      time.Duration `yaml:"verify_duration" env-default:"24h"`
+	// Минимальный интервал между повторными письмами подтверждения
+	VerifyResendInterval time.Duration `yaml:"verify_resend_interval" env-default:"1m"`
+	MFA                  MFA           `yaml:"mfa"`
+	Lockout              Lockout       `yaml:"lockout"`
+	OAuth                OAuth         `yaml:"oauth"`
+	// Пустой active_key_id означает подпись HS256 общим access_secret
+	ActiveKeyID string       `yaml:"active_key_id" env:"AUTH_ACTIVE_KEY_ID"`
+	SigningKeys []SigningKey `yaml:"signing_keys"`
+}
+
+type MFA struct {
+	Issuer string `yaml:"issuer" env-default:"Dev Blog"`
+	// Ключ шифрования TOTP-секретов; его смена делает уже включённую 2FA недоступной
+	EncryptionKey     string        `yaml:"encryption_key" env:"AUTH_MFA_ENCRYPTION_KEY"`
+	ChallengeDuration time.Duration `yaml:"challenge_duration" env-default:"5m"`
+}
+
+// Lockout - защита входа от подбора пароля, см. service.LockoutConfig
+type Lockout struct {
+	Window         time.Duration `yaml:"window" env-default:"1h"`
+	FreeAttempts   int           `yaml:"free_attempts" env-default:"5"`
+	IPFreeAttempts int           `yaml:"ip_free_attempts" env-default:"20"`
+	BaseDelay      time.Duration `yaml:"base_delay" env-default:"1s"`
+	MaxDelay       time.Duration `yaml:"max_delay" env-default:"15m"`
+	LockThreshold  int           `yaml:"lock_threshold" env-default:"10"`
+	LockDuration   time.Duration `yaml:"lock_duration" env-default:"30m"`
+}
+
+type OAuth struct {
+	StateDuration time.Duration `yaml:"state_duration" env-default:"10m"`
+	// GitHub включается заданным client_id
+	GitHub GitHubOAuth `yaml:"github"`
+	// OIDC - провайдеры OpenID Connect; name становится частью пути /auth/oauth/{name}
+	OIDC []OIDCProvider `yaml:"oidc"`
+}
+
+type GitHubOAuth struct {
+	ClientID     string `yaml:"client_id" env:"AUTH_GITHUB_CLIENT_ID"`
+	ClientSecret string `yaml:"client_secret" env:"AUTH_GITHUB_CLIENT_SECRET"`
+	RedirectURL  string `yaml:"redirect_url"`
+}
+
+type OIDCProvider struct {
+	Name         string   `yaml:"name"`
+	IssuerURL    string   `yaml:"issuer_url"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+type SigningKey struct {
+	ID             string `yaml:"id"`
+	Algorithm      string `yaml:"algorithm"`
+	PrivateKeyPath string `yaml:"private_key_path"`
 }
 
 type GRPC struct {
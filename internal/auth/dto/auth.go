@@ -1,12 +1,129 @@
 package dto
 
+import "time"
+
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=4,max=50"`
 }
 
+// ClientInfo - сведения о клиенте из HTTP-запроса
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginResponse при включённой 2FA содержит только mfa_token и его срок действия в expires_in
 type LoginResponse struct {
-	Token     string `json:"access_token"`
-	Type      string `json:"token_type"`
-	ExpiresIn int64  `json:"expires_in"`
+	Token            string `json:"access_token,omitempty"`
+	Type             string `json:"token_type,omitempty"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	MFARequired      bool   `json:"mfa_required,omitempty"`
+	MFAToken         string `json:"mfa_token,omitempty"`
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
+
+type TOTPEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TOTPConfirmRequest struct {
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type TOTPDisableRequest struct {
+	Password string `json:"password" validate:"required,max=50"`
+	Code     string `json:"code" validate:"required,max=20"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+// SessionResp - активный вход пользователя; Current отмечает сессию, из которой сделан запрос
+type SessionResp struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+type APIKeyCreateRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required,min=1,max=365"`
+}
+
+type APIKeyResp struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreateResponse содержит сам ключ; он показывается только при создании
+type APIKeyCreateResponse struct {
+	APIKeyResp
+	Key string `json:"key"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=4,max=50"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// JWK - публичный ключ в формате RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// OAuthStart - адрес провайдера и значение cookie, привязывающее state к браузеру, начавшему вход
+type OAuthStart struct {
+	AuthURL      string
+	StateBinding string
+	ExpiresAt    time.Time
 }
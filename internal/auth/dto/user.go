@@ -12,7 +12,15 @@ type UserUpdateRequest struct {
 	Username *string `json:"username" validate:"omitempty,min=3,max=30"`
 	Email    *string `json:"email" validate:"omitempty,email"`
 	Password *string `json:"password" validate:"omitempty,min=4,max=50"`
-	Role     *string `json:"role" validate:"oneof=unverified user admin"`
+	Role     *string `json:"role" validate:"omitempty,oneof=unverified user admin"`
+}
+
+// ProfileUpdateRequest - изменение своего профиля; для смены email или пароля нужен текущий пароль
+type ProfileUpdateRequest struct {
+	Username        *string `json:"username" validate:"omitempty,min=3,max=30"`
+	Email           *string `json:"email" validate:"omitempty,email"`
+	Password        *string `json:"password" validate:"omitempty,min=4,max=50"`
+	CurrentPassword *string `json:"current_password" validate:"omitempty,max=50"`
 }
 
 type UserResp struct {
@@ -21,4 +29,6 @@ type UserResp struct {
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
+	// PendingEmail - новый адрес, ожидающий подтверждения
+	PendingEmail string `json:"pending_email,omitempty"`
 }
@@ -1,13 +1,42 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrUserAlreadyExists  = errors.New("user already exists")
 	ErrInvalidUserID      = errors.New("invalid user id")
 	ErrInvalidPassword    = errors.New("invalid password")
+	ErrPasswordRequired   = errors.New("current password required")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrTokenExpired       = errors.New("token expired")
+	ErrTokenReused        = errors.New("refresh token reused")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrAccountLocked      = errors.New("account locked")
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrUnknownProvider    = errors.New("unknown oauth provider")
+	ErrProviderFailed     = errors.New("oauth provider error")
+	ErrEmailNotVerified   = errors.New("provider did not confirm email")
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidScope       = errors.New("invalid api key scope")
 )
+
+// AccountLockedError сообщает, до какого момента вход заблокирован; errors.Is(err, ErrAccountLocked) для неё истинно
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return "account locked until " + e.Until.Format(time.RFC3339)
+}
+
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
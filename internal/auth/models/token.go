@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// PublicKey - публичная часть ключа подписи в DER (PKIX) для локальной проверки токенов другими сервисами
+type PublicKey struct {
+	KeyID     string
+	Algorithm string
+	DER       []byte
+}
+
+// TOTP - второй фактор пользователя; Secret хранится зашифрованным
+type TOTP struct {
+	UserID       string
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// Session - вход пользователя с конкретного устройства; ID совпадает с FamilyID его refresh токенов
+type Session struct {
+	ID         string
+	UserID     string
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  *time.Time
+}
+
+// APIKey - персональный ключ для скриптов и CI; сам ключ не хранится, только его хеш
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
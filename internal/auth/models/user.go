@@ -8,6 +8,9 @@ type User struct {
 	Email             string
 	PasswordHash      string
 	VerificationToken string
-	Role              string
-	CreatedAt         time.Time
+	// VerificationExpiresAt и VerificationSentAt заданы, пока email не подтверждён
+	VerificationExpiresAt *time.Time
+	VerificationSentAt    *time.Time
+	Role                  string
+	CreatedAt             time.Time
 }
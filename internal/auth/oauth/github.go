@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	githubAuthorizeURL = "https://github.com/login/oauth/authorize"
+	githubTokenURL     = "https://github.com/login/oauth/access_token"
+	githubUserURL      = "https://api.github.com/user"
+	githubEmailsURL    = "https://api.github.com/user/emails"
+	githubScope        = "read:user user:email"
+)
+
+type GitHubConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// GitHubProvider - вход через GitHub OAuth App; GitHub не поддерживает OIDC для пользователей,
+// поэтому профиль и подтверждённый email берутся из REST API
+type GitHubProvider struct {
+	cfg    GitHubConfig
+	client *http.Client
+}
+
+func NewGitHubProvider(cfg GitHubConfig) *GitHubProvider {
+	return &GitHubProvider{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+func (p *GitHubProvider) AuthCodeURL(_ context.Context, state, codeChallenge string) (string, error) {
+	return authCodeURL(githubAuthorizeURL, p.cfg.ClientID, p.cfg.RedirectURL, githubScope, state, codeChallenge)
+}
+
+type githubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+}
+
+type githubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func (p *GitHubProvider) Exchange(ctx context.Context, code, codeVerifier string) (*Identity, error) {
+	accessToken, err := exchangeCode(ctx, p.client, githubTokenURL,
+		p.cfg.ClientID, p.cfg.ClientSecret, p.cfg.RedirectURL, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var user githubUser
+	if err = getJSON(ctx, p.client, githubUserURL, accessToken, &user); err != nil {
+		return nil, fmt.Errorf("get github user: %w", err)
+	}
+
+	var emails []githubEmail
+	if err = getJSON(ctx, p.client, githubEmailsURL, accessToken, &emails); err != nil {
+		return nil, fmt.Errorf("get github emails: %w", err)
+	}
+
+	identity := &Identity{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: user.Login,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,143 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Identity - сведения о пользователе, полученные от провайдера
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+const requestTimeout = 10 * time.Second
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// NewCodeVerifier возвращает code_verifier для PKCE (RFC 7636)
+func NewCodeVerifier() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("generate code verifier: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// CodeChallenge вычисляет code_challenge методом S256
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func authCodeURL(endpoint, clientID, redirectURL, scope, state, challenge string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parse authorization endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURL)
+	q.Set("scope", scope)
+	q.Set("state", state)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchangeCode обменивает код авторизации на access токен провайдера (client_secret_post)
+func exchangeCode(ctx context.Context, client *http.Client, tokenURL, clientID, clientSecret, redirectURL, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {clientID},
+		"code_verifier": {verifier},
+	}
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err = doJSON(client, req, &token); err != nil {
+		return "", fmt.Errorf("exchange code: %w", err)
+	}
+	if token.Error != "" {
+		return "", fmt.Errorf("exchange code: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("exchange code: no access token in response")
+	}
+
+	return token.AccessToken, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint, accessToken string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	return doJSON(client, req, dst)
+}
+
+// doJSON выполняет запрос и разбирает JSON-ответ; тело ответа с ошибкой OAuth тоже разбирается
+func doJSON(client *http.Client, req *http.Request, dst any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr tokenResponse
+		if json.Unmarshal(body, &oauthErr) == nil && oauthErr.Error != "" {
+			return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, oauthErr.Error, oauthErr.ErrorDescription)
+		}
+		return fmt.Errorf("%s %s: unexpected status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCProvider - любой провайдер OpenID Connect (Google, Keycloak, локальный mock IdP).
+// Адреса берутся из discovery-документа issuer'а, данные пользователя - из userinfo.
+// ID token и nonce не проверяются: личность берётся только из userinfo, запрошенного по access токену,
+// который получен напрямую от token endpoint по TLS в обмен на код с PKCE-верификатором.
+type OIDCProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *oidcEndpoints
+}
+
+type oidcEndpoints struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func NewOIDCProvider(cfg OIDCConfig) *OIDCProvider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &OIDCProvider{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+// discover загружает discovery-документ при первом обращении, чтобы сервис стартовал и без доступного IdP
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcEndpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	issuer := strings.TrimSuffix(p.cfg.IssuerURL, "/")
+
+	var endpoints oidcEndpoints
+	if err := getJSON(ctx, p.client, issuer+"/.well-known/openid-configuration", "", &endpoints); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if strings.TrimSuffix(endpoints.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch: %q", endpoints.Issuer)
+	}
+	if endpoints.AuthorizationEndpoint == "" || endpoints.TokenEndpoint == "" || endpoints.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery: incomplete provider metadata")
+	}
+
+	p.endpoints = &endpoints
+	return p.endpoints, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, codeChallenge string) (string, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	scope := strings.Join(p.cfg.Scopes, " ")
+	return authCodeURL(endpoints.AuthorizationEndpoint, p.cfg.ClientID, p.cfg.RedirectURL, scope, state, codeChallenge)
+}
+
+type oidcUserInfo struct {
+	Subject           string          `json:"sub"`
+	Email             string          `json:"email"`
+	EmailVerified     json.RawMessage `json:"email_verified"`
+	PreferredUsername string          `json:"preferred_username"`
+	Name              string          `json:"name"`
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (*Identity, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := exchangeCode(ctx, p.client, endpoints.TokenEndpoint,
+		p.cfg.ClientID, p.cfg.ClientSecret, p.cfg.RedirectURL, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var info oidcUserInfo
+	if err = getJSON(ctx, p.client, endpoints.UserinfoEndpoint, accessToken, &info); err != nil {
+		return nil, fmt.Errorf("get userinfo: %w", err)
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("get userinfo: empty subject")
+	}
+
+	username := info.PreferredUsername
+	if username == "" {
+		username = info.Name
+	}
+
+	return &Identity{
+		Subject: info.Subject,
+		Email:   info.Email,
+		// Некоторые провайдеры отдают email_verified строкой "true"
+		EmailVerified: strings.Trim(string(info.EmailVerified), `"`) == "true",
+		Username:      username,
+	}, nil
+}
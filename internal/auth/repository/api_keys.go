@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (t *TokensRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	q := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	err := t.db.QueryRow(ctx, q, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create api key: %w", err)
+	}
+	return nil
+}
+
+// ListAPIKeys возвращает неотозванные ключи пользователя, включая истёкшие
+func (t *TokensRepository) ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC`
+
+	rows, err := t.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// UseAPIKey находит действующий ключ по хешу и отмечает его использование.
+// last_used_at обновляется не чаще раза в минуту, чтобы частые запросы CI не писали в базу каждый раз.
+func (t *TokensRepository) UseAPIKey(ctx context.Context, hash string) (*models.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()`
+
+	key, err := scanAPIKey(t.db.QueryRow(ctx, q, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+
+	q = `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+	if _, err = t.db.Exec(ctx, q, key.ID); err != nil {
+		return nil, fmt.Errorf("touch api key %s: %w", key.ID, err)
+	}
+
+	return key, nil
+}
+
+func (t *TokensRepository) RevokeAPIKey(ctx context.Context, id, userID string) error {
+	q := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := t.db.Exec(ctx, q, id, userID)
+	if err != nil {
+		return fmt.Errorf("revoke api key %s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+// CreateEmailChangeRequest сохраняет ожидающий подтверждения адрес; прежние неподтверждённые запросы удаляются,
+// так что действует только последняя ссылка
+func (u *UsersRepository) CreateEmailChangeRequest(ctx context.Context, userID, newEmail, hash string, expiresAt time.Time) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `DELETE FROM email_change_requests WHERE user_id = $1 AND confirmed_at IS NULL`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return fmt.Errorf("delete pending email changes %s: %w", userID, err)
+	}
+
+	q = `INSERT INTO email_change_requests (user_id, new_email, token_hash, expires_at) VALUES ($1, $2, $3, $4)`
+	if _, err = tx.Exec(ctx, q, userID, newEmail, hash, expiresAt); err != nil {
+		return fmt.Errorf("create email change request: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// GetPendingEmail возвращает адрес из действующего запроса на смену или пустую строку
+func (u *UsersRepository) GetPendingEmail(ctx context.Context, userID string) (string, error) {
+	q := `
+		SELECT new_email FROM email_change_requests
+		WHERE user_id = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var email string
+	if err := u.db.QueryRow(ctx, q, userID).Scan(&email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get pending email %s: %w", userID, err)
+	}
+	return email, nil
+}
+
+// ConfirmEmailChange гасит токен и меняет email в одной транзакции
+func (u *UsersRepository) ConfirmEmailChange(ctx context.Context, hash string) (string, error) {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID, newEmail string
+	q := `
+		UPDATE email_change_requests SET confirmed_at = NOW()
+		WHERE token_hash = $1 AND confirmed_at IS NULL AND expires_at > NOW()
+		RETURNING user_id, new_email`
+	if err = tx.QueryRow(ctx, q, hash).Scan(&userID, &newEmail); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrInvalidToken
+		}
+		return "", fmt.Errorf("consume email change token: %w", err)
+	}
+
+	q = `UPDATE users SET email = $1 WHERE id = $2`
+	if _, err = tx.Exec(ctx, q, newEmail, userID); err != nil {
+		// Адрес мог занять другой пользователь, пока запрос ждал подтверждения
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return "", models.ErrUserAlreadyExists
+		}
+		return "", fmt.Errorf("update email %s: %w", userID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return userID, nil
+}
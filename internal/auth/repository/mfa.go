@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+)
+
+// UpsertTOTP сохраняет новый неподтверждённый секрет; повторная регистрация заменяет прежний,
+// пока второй фактор не подтверждён
+func (u *UsersRepository) UpsertTOTP(ctx context.Context, userID, secret string) error {
+	q := `
+		INSERT INTO user_totp (user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, confirmed_at = NULL, last_used_step = 0, created_at = NOW()
+		WHERE user_totp.confirmed_at IS NULL`
+
+	result, err := u.db.Exec(ctx, q, userID, secret)
+	if err != nil {
+		return fmt.Errorf("upsert totp %s: %w", userID, err)
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (u *UsersRepository) GetTOTP(ctx context.Context, userID string) (*models.TOTP, error) {
+	q := `SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM user_totp WHERE user_id = $1`
+
+	var totp models.TOTP
+	err := u.db.QueryRow(ctx, q, userID).Scan(
+		&totp.UserID,
+		&totp.Secret,
+		&totp.ConfirmedAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrMFANotEnabled
+		}
+		return nil, fmt.Errorf("get totp %s: %w", userID, err)
+	}
+	return &totp, nil
+}
+
+// ConfirmTOTP включает второй фактор и заменяет коды восстановления в одной транзакции
+func (u *UsersRepository) ConfirmTOTP(ctx context.Context, userID string, step int64, codeHashes []string) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `
+		UPDATE user_totp SET confirmed_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL`
+	result, err := tx.Exec(ctx, q, userID, step)
+	if err != nil {
+		return fmt.Errorf("confirm totp %s: %w", userID, err)
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrMFAAlreadyEnabled
+	}
+
+	q = `DELETE FROM mfa_recovery_codes WHERE user_id = $1`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return fmt.Errorf("delete recovery codes %s: %w", userID, err)
+	}
+
+	q = `INSERT INTO mfa_recovery_codes (user_id, code_hash) SELECT $1, unnest($2::text[])`
+	if _, err = tx.Exec(ctx, q, userID, codeHashes); err != nil {
+		return fmt.Errorf("create recovery codes %s: %w", userID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// UseTOTPStep запоминает использованный шаг; false означает, что код этого или более позднего шага уже предъявлялся
+func (u *UsersRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	q := `UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+
+	result, err := u.db.Exec(ctx, q, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("use totp step %s: %w", userID, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// UseRecoveryCode гасит одноразовый код восстановления
+func (u *UsersRepository) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	q := `UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := u.db.Exec(ctx, q, userID, hash)
+	if err != nil {
+		return false, fmt.Errorf("use recovery code %s: %w", userID, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (u *UsersRepository) DeleteTOTP(ctx context.Context, userID string) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes %s: %w", userID, err)
+	}
+	if _, err = tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete totp %s: %w", userID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
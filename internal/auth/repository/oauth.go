@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+	"time"
+)
+
+// CreateOAuthState сохраняет state начатого входа; заодно удаляются просроченные записи
+func (u *UsersRepository) CreateOAuthState(ctx context.Context, hash, provider, codeVerifier string, expiresAt time.Time) error {
+	q := `INSERT INTO oauth_states (state_hash, provider, code_verifier, expires_at) VALUES ($1, $2, $3, $4)`
+
+	if _, err := u.db.Exec(ctx, q, hash, provider, codeVerifier, expiresAt); err != nil {
+		return fmt.Errorf("create oauth state: %w", err)
+	}
+
+	if _, err := u.db.Exec(ctx, `DELETE FROM oauth_states WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("cleanup oauth states: %w", err)
+	}
+	return nil
+}
+
+// ConsumeOAuthState удаляет state и возвращает его code_verifier, поэтому каждый state срабатывает один раз
+func (u *UsersRepository) ConsumeOAuthState(ctx context.Context, hash, provider string) (string, error) {
+	q := `DELETE FROM oauth_states WHERE state_hash = $1 AND provider = $2 RETURNING code_verifier, expires_at`
+
+	var (
+		codeVerifier string
+		expiresAt    time.Time
+	)
+	if err := u.db.QueryRow(ctx, q, hash, provider).Scan(&codeVerifier, &expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrInvalidToken
+		}
+		return "", fmt.Errorf("consume oauth state: %w", err)
+	}
+
+	if time.Now().After(expiresAt) {
+		return "", models.ErrTokenExpired
+	}
+	return codeVerifier, nil
+}
+
+func (u *UsersRepository) GetUserByIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	q := `SELECT ` + userColumns + ` FROM users
+		WHERE id = (SELECT user_id FROM user_identities WHERE provider = $1 AND subject = $2)`
+
+	user, err := scanUser(u.db.QueryRow(ctx, q, provider, subject))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user by %s identity: %w", provider, err)
+	}
+	return user, nil
+}
+
+func (u *UsersRepository) LinkIdentity(ctx context.Context, userID, provider, subject, email string) error {
+	q := `INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)`
+
+	if _, err := u.db.Exec(ctx, q, provider, subject, userID, email); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("link %s identity: %w", provider, err)
+	}
+	return nil
+}
+
+// ClaimUnverifiedUser передаёт неподтверждённый аккаунт владельцу email, подтверждённого у провайдера.
+// В одной транзакции аккаунт подтверждается, пароль сбрасывается, все токены, сессии и API ключи
+// прежнего владельца отзываются и привязывается аккаунт провайдера.
+func (u *UsersRepository) ClaimUnverifiedUser(ctx context.Context, user *models.User, provider, subject, email string) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `
+		UPDATE users SET role = $2, password_hash = '', verification_token = NULL
+		WHERE id = $1 AND role = $3`
+	result, err := tx.Exec(ctx, q, user.ID, authz.RoleUser, authz.RoleUnverified)
+	if err != nil {
+		return fmt.Errorf("claim user %s: %w", user.ID, err)
+	}
+	if result.RowsAffected() == 0 {
+		// Аккаунт успели подтвердить: передавать его уже нельзя
+		return models.ErrUserAlreadyExists
+	}
+
+	q = `
+		INSERT INTO user_revocations (user_id, revoked_before) VALUES ($1, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before`
+	if _, err = tx.Exec(ctx, q, user.ID); err != nil {
+		return fmt.Errorf("revoke user %s tokens: %w", user.ID, err)
+	}
+
+	for _, q = range []string{
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		`UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		`UPDATE api_keys SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+	} {
+		if _, err = tx.Exec(ctx, q, user.ID); err != nil {
+			return fmt.Errorf("revoke user %s credentials: %w", user.ID, err)
+		}
+	}
+
+	q = `INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)`
+	if _, err = tx.Exec(ctx, q, provider, subject, user.ID, email); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("link %s identity: %w", provider, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+
+	user.Role = authz.RoleUser
+	user.PasswordHash = ""
+	user.VerificationToken = ""
+	return nil
+}
+
+// CreateOAuthUser создаёт пользователя без пароля сразу с привязанным аккаунтом провайдера
+func (u *UsersRepository) CreateOAuthUser(ctx context.Context, user *models.User, provider, subject string) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `
+		INSERT INTO users (username, email, password_hash, role) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	err = tx.QueryRow(ctx, q, user.Username, user.Email, user.PasswordHash, user.Role).
+		Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	q = `INSERT INTO user_identities (provider, subject, user_id, email) VALUES ($1, $2, $3, $4)`
+	if _, err = tx.Exec(ctx, q, provider, subject, user.ID, user.Email); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrUserAlreadyExists
+		}
+		return fmt.Errorf("link %s identity: %w", provider, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+func (u *UsersRepository) CreatePasswordResetToken(ctx context.Context, userID, hash string, expiresAt time.Time) error {
+	q := `INSERT INTO password_reset_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3)`
+
+	if _, err := u.db.Exec(ctx, q, userID, hash, expiresAt); err != nil {
+		return fmt.Errorf("create password reset token: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword гасит токен сброса и меняет пароль в одной транзакции.
+// Остальные невостребованные токены пользователя тоже гасятся.
+func (u *UsersRepository) ResetPassword(ctx context.Context, hash, passwordHash string) (string, error) {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID string
+	q := `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`
+	if err = tx.QueryRow(ctx, q, hash).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", models.ErrInvalidToken
+		}
+		return "", fmt.Errorf("consume password reset token: %w", err)
+	}
+
+	q = `UPDATE users SET password_hash = $1 WHERE id = $2`
+	if _, err = tx.Exec(ctx, q, passwordHash, userID); err != nil {
+		return "", fmt.Errorf("update password %s: %w", userID, err)
+	}
+
+	q = `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return "", fmt.Errorf("expire password reset tokens %s: %w", userID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit tx: %w", err)
+	}
+	return userID, nil
+}
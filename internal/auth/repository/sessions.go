@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+)
+
+func (t *TokensRepository) CreateSession(ctx context.Context, session *models.Session) error {
+	q := `
+		INSERT INTO sessions (user_id, user_agent, ip) VALUES ($1, $2, $3)
+		RETURNING id, created_at, last_used_at`
+
+	err := t.db.QueryRow(ctx, q, session.UserID, session.UserAgent, session.IP).
+		Scan(&session.ID, &session.CreatedAt, &session.LastUsedAt)
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+	return nil
+}
+
+// TouchSession отмечает использование сессии при обновлении токенов. Возвращает false,
+// если сессия отозвана или не существует. Пустые ip и userAgent не затирают сохранённые.
+func (t *TokensRepository) TouchSession(ctx context.Context, id, ip, userAgent string) (bool, error) {
+	q := `
+		UPDATE sessions
+		SET last_used_at = NOW(),
+		    ip = COALESCE(NULLIF($2, ''), ip),
+		    user_agent = COALESCE(NULLIF($3, ''), user_agent)
+		WHERE id = $1 AND revoked_at IS NULL`
+
+	result, err := t.db.Exec(ctx, q, id, ip, userAgent)
+	if err != nil {
+		return false, fmt.Errorf("touch session %s: %w", id, err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ListSessions возвращает сессии, в которых ещё есть действующий refresh токен
+func (t *TokensRepository) ListSessions(ctx context.Context, userID string) ([]*models.Session, error) {
+	q := `
+		SELECT s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_used_at, s.revoked_at
+		FROM sessions s
+		WHERE s.user_id = $1 AND s.revoked_at IS NULL
+		  AND EXISTS(SELECT 1 FROM refresh_tokens rt
+		             WHERE rt.family_id = s.id AND rt.used_at IS NULL AND rt.revoked_at IS NULL
+		               AND rt.expires_at > NOW())
+		ORDER BY s.last_used_at DESC`
+
+	rows, err := t.db.Query(ctx, q, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		var session models.Session
+		err = rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastUsedAt,
+			&session.RevokedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession отзывает сессию пользователя вместе с её refresh токенами
+func (t *TokensRepository) RevokeSession(ctx context.Context, id, userID string) error {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := tx.Exec(ctx, q, id, userID)
+	if err != nil {
+		return fmt.Errorf("revoke session %s: %w", id, err)
+	}
+	if result.RowsAffected() == 0 {
+		return models.ErrSessionNotFound
+	}
+
+	q = `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	if _, err = tx.Exec(ctx, q, id); err != nil {
+		return fmt.Errorf("revoke session %s refresh tokens: %w", id, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
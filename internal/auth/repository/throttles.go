@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+// ThrottlesRepository хранит счётчики неудачных входов в базе, чтобы блокировки
+// переживали перезапуск и действовали на всех репликах
+type ThrottlesRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewThrottlesRepository(pool *pgxpool.Pool) *ThrottlesRepository {
+	return &ThrottlesRepository{
+		db: pool,
+	}
+}
+
+// GetLockedUntil возвращает момент окончания блокировки или nil, если блокировки нет
+func (t *ThrottlesRepository) GetLockedUntil(ctx context.Context, scope, subject string) (*time.Time, error) {
+	q := `SELECT locked_until FROM login_throttles WHERE scope = $1 AND subject = $2 AND locked_until > NOW()`
+
+	var lockedUntil time.Time
+	if err := t.db.QueryRow(ctx, q, scope, subject).Scan(&lockedUntil); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get login throttle %s:%s: %w", scope, subject, err)
+	}
+	return &lockedUntil, nil
+}
+
+// RegisterFailure увеличивает счётчик неудач и возвращает его новое значение.
+// Если с прошлой неудачи прошло больше window, счёт начинается заново.
+func (t *ThrottlesRepository) RegisterFailure(ctx context.Context, scope, subject string, window time.Duration) (int, error) {
+	q := `
+		INSERT INTO login_throttles (scope, subject, failures, last_failure_at) VALUES ($1, $2, 1, NOW())
+		ON CONFLICT (scope, subject) DO UPDATE
+		SET failures = CASE
+		        WHEN login_throttles.last_failure_at < NOW() - make_interval(secs => $3) THEN 1
+		        ELSE login_throttles.failures + 1
+		    END,
+		    last_failure_at = NOW()
+		RETURNING failures`
+
+	var failures int
+	if err := t.db.QueryRow(ctx, q, scope, subject, window.Seconds()).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("register login failure %s:%s: %w", scope, subject, err)
+	}
+	return failures, nil
+}
+
+// Lock продлевает блокировку до until; более долгая блокировка не сокращается
+func (t *ThrottlesRepository) Lock(ctx context.Context, scope, subject string, until time.Time) error {
+	q := `UPDATE login_throttles SET locked_until = GREATEST(locked_until, $3) WHERE scope = $1 AND subject = $2`
+
+	if _, err := t.db.Exec(ctx, q, scope, subject, until); err != nil {
+		return fmt.Errorf("lock %s:%s: %w", scope, subject, err)
+	}
+	return nil
+}
+
+// Reset сбрасывает счётчик после успешного входа и удаляет давно устаревшие записи
+func (t *ThrottlesRepository) Reset(ctx context.Context, scope, subject string, window time.Duration) error {
+	q := `DELETE FROM login_throttles WHERE scope = $1 AND subject = $2`
+	if _, err := t.db.Exec(ctx, q, scope, subject); err != nil {
+		return fmt.Errorf("reset login throttle %s:%s: %w", scope, subject, err)
+	}
+
+	q = `
+		DELETE FROM login_throttles
+		WHERE last_failure_at < NOW() - make_interval(secs => $1) AND (locked_until IS NULL OR locked_until < NOW())`
+	if _, err := t.db.Exec(ctx, q, window.Seconds()); err != nil {
+		return fmt.Errorf("delete stale login throttles: %w", err)
+	}
+	return nil
+}
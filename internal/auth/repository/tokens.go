@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+type TokensRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokensRepository(pool *pgxpool.Pool) *TokensRepository {
+	return &TokensRepository{
+		db: pool,
+	}
+}
+
+// CreateRefreshToken сохраняет токен; пустой FamilyID открывает новое семейство
+func (t *TokensRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	q := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		VALUES ($1, COALESCE(NULLIF($2, '')::uuid, uuid_generate_v4()), $3, $4)
+		RETURNING id, family_id, created_at`
+
+	err := t.db.QueryRow(ctx, q, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt).
+		Scan(&token.ID, &token.FamilyID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (t *TokensRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	q := `
+        SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+        FROM refresh_tokens WHERE token_hash = $1`
+	err := t.db.QueryRow(ctx, q, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, models.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("get refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed помечает токен использованным. Возвращает false, если токен уже был
+// использован или отозван - в том числе параллельным запросом с тем же токеном.
+func (t *TokensRepository) MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error) {
+	q := `
+		UPDATE refresh_tokens SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`
+
+	result, err := t.db.Exec(ctx, q, id)
+	if err != nil {
+		return false, fmt.Errorf("mark refresh token %s used: %w", id, err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// RevokeTokenFamily отзывает семейство refresh токенов и сессию, которой оно принадлежит
+func (t *TokensRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	q := `
+		WITH revoked_session AS (
+		    UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+
+	if _, err := t.db.Exec(ctx, q, familyID); err != nil {
+		return fmt.Errorf("revoke token family %s: %w", familyID, err)
+	}
+	return nil
+}
+
+// RevokeRefreshToken отзывает семейство, к которому относится refresh токен пользователя, и его сессию
+func (t *TokensRepository) RevokeRefreshToken(ctx context.Context, hash, userID string) error {
+	q := `
+		WITH family AS (
+		    SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND user_id = $2
+		), revoked_session AS (
+		    UPDATE sessions SET revoked_at = NOW() WHERE id = (SELECT family_id FROM family) AND revoked_at IS NULL
+		)
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE family_id = (SELECT family_id FROM family)
+		  AND revoked_at IS NULL`
+
+	if _, err := t.db.Exec(ctx, q, hash, userID); err != nil {
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken заносит jti в список отозванных; заодно удаляются записи, чей exp уже прошёл
+func (t *TokensRepository) RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error {
+	q := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING`
+
+	if _, err := t.db.Exec(ctx, q, jti, userID, expiresAt); err != nil {
+		return fmt.Errorf("revoke access token %s: %w", jti, err)
+	}
+
+	if _, err := t.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("cleanup revoked tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens отзывает все access и refresh токены пользователя, выпущенные до текущего момента
+func (t *TokensRepository) RevokeUserTokens(ctx context.Context, userID string) error {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := `
+		INSERT INTO user_revocations (user_id, revoked_before) VALUES ($1, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return fmt.Errorf("revoke user %s tokens: %w", userID, err)
+	}
+
+	q = `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return fmt.Errorf("revoke user %s refresh tokens: %w", userID, err)
+	}
+
+	q = `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err = tx.Exec(ctx, q, userID); err != nil {
+		return fmt.Errorf("revoke user %s sessions: %w", userID, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked проверяет отзыв конкретного токена, его сессии и выход пользователя из всех сессий.
+// iat в JWT хранится с точностью до секунды, поэтому сравнение идёт по секундам и строгое:
+// токен, выданный в ту же секунду, что и отзыв (например, при новом входе сразу после смены пароля), остаётся действительным.
+// Пустой sessionID (токены, выпущенные до появления сессий) проверку сессии пропускает.
+func (t *TokensRepository) IsAccessTokenRevoked(ctx context.Context, jti, userID, sessionID string, issuedAt time.Time) (bool, error) {
+	q := `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+		    OR EXISTS(SELECT 1 FROM user_revocations
+		              WHERE user_id = $2 AND date_trunc('second', revoked_before) > $3)
+		    OR EXISTS(SELECT 1 FROM sessions
+		              WHERE id = NULLIF($4, '')::uuid AND revoked_at IS NOT NULL)`
+
+	var revoked bool
+	if err := t.db.QueryRow(ctx, q, jti, userID, issuedAt, sessionID).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("check token revocation: %w", err)
+	}
+	return revoked, nil
+}
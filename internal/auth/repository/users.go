@@ -5,8 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
 )
 
 type UsersRepository struct {
@@ -19,14 +22,36 @@ func NewUsersRepository(pool *pgxpool.Pool) *UsersRepository {
 	}
 }
 
+const userColumns = `id, email, username, password_hash, role, COALESCE(verification_token, ''),
+	verification_expires_at, verification_sent_at, created_at`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.VerificationToken,
+		&user.VerificationExpiresAt,
+		&user.VerificationSentAt,
+		&user.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (u *UsersRepository) CreateUser(ctx context.Context, user *models.User) error {
 	q := `
-		INSERT INTO users (username, email, password_hash, verification_token) 
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (username, email, password_hash, verification_token, verification_expires_at, verification_sent_at) 
+		VALUES ($1, $2, $3, $4, $5, NOW())
 		RETURNING id, created_at
 `
 
-	err := u.db.QueryRow(ctx, q, user.Username, user.Email, user.PasswordHash, user.VerificationToken).
+	err := u.db.QueryRow(ctx, q, user.Username, user.Email, user.PasswordHash, user.VerificationToken, user.VerificationExpiresAt).
 		Scan(&user.ID, &user.CreatedAt)
 
 	if err != nil {
@@ -37,77 +62,59 @@ func (u *UsersRepository) CreateUser(ctx context.Context, user *models.User) err
 }
 
 func (u *UsersRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
-	var user models.User
-	q := `
-        SELECT id, email, username, password_hash, role, verification_token, created_at 
-        FROM users WHERE id = $1`
-	err := u.db.QueryRow(ctx, q, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Role,
-		&user.VerificationToken,
-		&user.CreatedAt)
+	q := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	user, err := scanUser(u.db.QueryRow(ctx, q, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("get user %v: %w", id, err)
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (u *UsersRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	var user models.User
-	q := `
-        SELECT id, email, username, password_hash, role, verification_token, created_at 
-        FROM users WHERE email = $1`
-	err := u.db.QueryRow(ctx, q, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Role,
-		&user.VerificationToken,
-		&user.CreatedAt)
+	q := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	user, err := scanUser(u.db.QueryRow(ctx, q, email))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("get user %v: %w", email, err)
 	}
-	return &user, nil
+	return user, nil
 }
 
-func (u *UsersRepository) FindByVerificationToken(ctx context.Context, token string) (*models.User, error) {
-	user := models.User{}
-	q := `
-        SELECT id, email, username, password_hash, role, verification_token, created_at 
-        FROM users WHERE verification_token = $1`
-	err := u.db.QueryRow(ctx, q, token).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Role,
-		&user.VerificationToken,
-		&user.CreatedAt,
-	)
+// FindByVerificationToken ищет пользователя по хешу токена подтверждения; срок действия проверяет сервис
+func (u *UsersRepository) FindByVerificationToken(ctx context.Context, hash string) (*models.User, error) {
+	q := `SELECT ` + userColumns + ` FROM users WHERE verification_token = $1`
+	user, err := scanUser(u.db.QueryRow(ctx, q, hash))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, models.ErrUserNotFound
 		}
-		return nil, fmt.Errorf("get user with token %v: %w", token, err)
+		return nil, fmt.Errorf("get user by verification token: %w", err)
 	}
-	return &user, nil
+	return user, nil
+}
+
+// SetVerificationToken выдаёт новый токен подтверждения, если предыдущий отправлен не раньше minInterval назад.
+// Проверка и запись выполняются одним запросом, поэтому параллельные повторы не проходят лимит.
+func (u *UsersRepository) SetVerificationToken(ctx context.Context, userID, hash string, expiresAt time.Time, minInterval time.Duration) (bool, error) {
+	q := `
+		UPDATE users SET verification_token = $1, verification_expires_at = $2, verification_sent_at = NOW()
+		WHERE id = $3 AND (verification_sent_at IS NULL OR verification_sent_at <= NOW() - make_interval(secs => $4))`
+
+	result, err := u.db.Exec(ctx, q, hash, expiresAt, userID, minInterval.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("set verification token %s: %w", userID, err)
+	}
+	return result.RowsAffected() > 0, nil
 }
 
 func (u *UsersRepository) ListUsers(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
-	q := `SELECT id, email, username, password_hash, role, verification_token, created_at 
-        FROM users 
-`
+	q := `SELECT ` + userColumns + ` FROM users`
 	rows, err := u.db.Query(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("get all users: %w", err)
@@ -115,20 +122,11 @@ func (u *UsersRepository) ListUsers(ctx context.Context) ([]*models.User, error)
 	defer rows.Close()
 
 	for rows.Next() {
-		var user models.User
-		err = rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Username,
-			&user.PasswordHash,
-			&user.Role,
-			&user.VerificationToken,
-			&user.CreatedAt,
-		)
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
-		users = append(users, &user)
+		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -143,6 +141,31 @@ func (u *UsersRepository) ListUsers(ctx context.Context) ([]*models.User, error)
 
 }
 
+// GetUsersByIDs возвращает найденных пользователей; отсутствующие id пропускаются
+func (u *UsersRepository) GetUsersByIDs(ctx context.Context, ids []string) ([]*models.User, error) {
+	q := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1::uuid[])`
+	rows, err := u.db.Query(ctx, q, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get users by ids: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("db rows fail: %w", err)
+	}
+
+	return users, nil
+}
+
 func (u *UsersRepository) UpdateUser(ctx context.Context, user *models.User) error {
 	q := `UPDATE users SET 
                  email = $1, 
@@ -156,6 +179,10 @@ func (u *UsersRepository) UpdateUser(ctx context.Context, user *models.User) err
 		ctx, q, user.Email, user.Username, user.PasswordHash, user.Role, user.VerificationToken, user.ID,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.ErrUserAlreadyExists
+		}
 		return fmt.Errorf("update user %s: %w", user.ID, err)
 	}
 	return nil
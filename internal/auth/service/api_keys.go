@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+	"slices"
+	"time"
+)
+
+// apiKeyPrefix отличает API ключи от JWT и позволяет находить их в логах и сканерах секретов
+const apiKeyPrefix = "dbp_"
+
+// newAPIKey возвращает ключ вида dbp_<prefix>_<secret> и его видимую часть dbp_<prefix>
+func newAPIKey() (key, prefix string) {
+	prefix = apiKeyPrefix + newTokenID()[:8]
+	return prefix + "_" + generateToken(), prefix
+}
+
+// apiKeyRole - API ключи не дают административных прав, даже если их владелец администратор
+func apiKeyRole(role string) string {
+	if role == roleAdmin {
+		return roleUser
+	}
+	return role
+}
+
+// CreateAPIKey выпускает ключ; сам ключ возвращается один раз, в базе остаётся только хеш
+func (s *UsersService) CreateAPIKey(ctx context.Context, userID string, req *dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error) {
+	const op = "users.CreateAPIKey"
+
+	user, err := s.usersRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+	for _, scope := range scopes {
+		permission := authz.Permission(scope)
+		if !slices.Contains(authz.APIKeyScopes, permission) || !authz.Can(apiKeyRole(user.Role), permission) {
+			return nil, fmt.Errorf("%s: %w: %s", op, models.ErrInvalidScope, scope)
+		}
+	}
+
+	key, prefix := newAPIKey()
+	apiKey := &models.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: time.Now().AddDate(0, 0, req.ExpiresInDays),
+	}
+	if err = s.tokensRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("api key created", "userID", userID, "prefix", prefix, "scopes", scopes)
+	return &dto.APIKeyCreateResponse{
+		APIKeyResp: apiKeyResp(apiKey),
+		Key:        key,
+	}, nil
+}
+
+func (s *UsersService) ListAPIKeys(ctx context.Context, userID string) ([]*dto.APIKeyResp, error) {
+	const op = "users.ListAPIKeys"
+
+	keys, err := s.tokensRepo.ListAPIKeys(ctx, userID)
+	if err != nil {
+		s.log.Errorw("failed to list api keys", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keysResp := make([]*dto.APIKeyResp, 0, len(keys))
+	for _, key := range keys {
+		resp := apiKeyResp(key)
+		keysResp = append(keysResp, &resp)
+	}
+	return keysResp, nil
+}
+
+func (s *UsersService) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	const op = "users.RevokeAPIKey"
+
+	if err := s.tokensRepo.RevokeAPIKey(ctx, keyID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("api key revoked", "userID", userID, "keyID", keyID)
+	return nil
+}
+
+func apiKeyResp(key *models.APIKey) dto.APIKeyResp {
+	return dto.APIKeyResp{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -11,38 +11,66 @@ import (
 const (
 	roleUnverified = "unverified"
 	roleUser       = "user"
-	roleAdmin      = "unverified"
+	roleAdmin      = "admin"
 )
 
 type UserProvider interface {
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetUsersByIDs(ctx context.Context, ids []string) ([]*models.User, error)
+}
+
+type APIKeyProvider interface {
+	UseAPIKey(ctx context.Context, hash string) (*models.APIKey, error)
 }
 
 type AuthService struct {
 	userProvider UserProvider
+	revocations  RevocationChecker
+	apiKeys      APIKeyProvider
+	revoked      *revokedCache
 	log          *zap.SugaredLogger
-	secret       string
+	keys         *KeySet
 }
 
-func NewAuthService(userProvider UserProvider, logger *zap.SugaredLogger, secret string) *AuthService {
+func NewAuthService(
+	userProvider UserProvider,
+	revocations RevocationChecker,
+	apiKeys APIKeyProvider,
+	logger *zap.SugaredLogger,
+	keys *KeySet,
+) *AuthService {
 	return &AuthService{
 		userProvider: userProvider,
+		revocations:  revocations,
+		apiKeys:      apiKeys,
+		revoked:      newRevokedCache(),
 		log:          logger,
-		secret:       secret,
+		keys:         keys,
 	}
 }
 
-func (a *AuthService) Auth(ctx context.Context, tokenString string) (userId, role string, err error) {
+// Auth проверяет access токен и возвращает пользователя, его текущую роль и сессию токена
+func (a *AuthService) Auth(ctx context.Context, tokenString string) (userId, role, sessionID string, err error) {
 	const op = "auth.Auth"
 
 	if tokenString == "" {
-		return "", roleUnverified, nil
+		return "", roleUnverified, "", nil
 	}
 
 	token, err := a.validateToken(tokenString)
 	if err != nil {
 		a.log.Errorw("failed to validate token", "error", err)
-		return "", roleUnverified, models.ErrInvalidToken
+		return "", roleUnverified, "", models.ErrInvalidToken
+	}
+
+	revoked, err := a.isRevoked(ctx, token)
+	if err != nil {
+		a.log.Errorw("failed to check token revocation", "userId", token.UserID, "error", err)
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+	if revoked {
+		a.log.Infow("revoked token rejected", "userId", token.UserID, "jti", token.ID)
+		return "", roleUnverified, "", models.ErrInvalidToken
 	}
 
 	a.log.Infow("authorizing user", "userId", token.UserID)
@@ -53,13 +81,36 @@ func (a *AuthService) Auth(ctx context.Context, tokenString string) (userId, rol
 			a.log.Errorw("orphaned token - user not found",
 				"user_id", token.UserID,
 				"token_email", token.Email)
-			return "", "", fmt.Errorf("%s: user not found", op)
+			return "", "", "", fmt.Errorf("%s: user not found", op)
 		}
 
 		a.log.Errorw("failed to get user", "userId", token.UserID, "error", err)
-		return "", "", fmt.Errorf("%s: %w", op, err)
+		return "", "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token.UserID, user.Role, token.SessionID, nil
+
+}
+
+// AuthAPIKey проверяет API ключ и возвращает владельца, его роль без административных прав и scopes ключа
+func (a *AuthService) AuthAPIKey(ctx context.Context, key string) (userId, role string, scopes []string, err error) {
+	const op = "auth.AuthAPIKey"
+
+	apiKey, err := a.apiKeys.UseAPIKey(ctx, hashToken(key))
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			return "", "", nil, models.ErrInvalidToken
+		}
+		a.log.Errorw("failed to check api key", "error", err)
+		return "", "", nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return token.UserID, user.Role, nil
+	user, err := a.userProvider.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		a.log.Errorw("failed to get api key owner", "userId", apiKey.UserID, "error", err)
+		return "", "", nil, fmt.Errorf("%s: %w", op, err)
+	}
 
+	a.log.Infow("authorizing api key", "userId", user.ID, "prefix", apiKey.Prefix)
+	return user.ID, apiKeyRole(user.Role), apiKey.Scopes, nil
 }
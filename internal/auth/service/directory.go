@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+	"slices"
+)
+
+// Методы для других сервисов: поиск пользователей и проверка прав по gRPC
+
+func (a *AuthService) GetUser(ctx context.Context, id string) (*models.User, error) {
+	const op = "auth.GetUser"
+
+	user, err := a.userProvider.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return user, nil
+}
+
+// GetUsers возвращает пользователей по списку id без повторов; ненайденные id пропускаются
+func (a *AuthService) GetUsers(ctx context.Context, ids []string) ([]*models.User, error) {
+	const op = "auth.GetUsers"
+
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	users, err := a.userProvider.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		a.log.Errorw("failed to get users", "count", len(ids), "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return users, nil
+}
+
+// CheckPermission сообщает, есть ли у пользователя право, и возвращает его текущую роль
+func (a *AuthService) CheckPermission(ctx context.Context, userID, permission string) (bool, string, error) {
+	const op = "auth.CheckPermission"
+
+	user, err := a.userProvider.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authz.Can(user.Role, authz.Permission(permission)), user.Role, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"strings"
+	"time"
+)
+
+// requestEmailChange не меняет email сразу: новый адрес ждёт подтверждения по ссылке из письма,
+// а на старый уходит уведомление о запросе
+func (s *UsersService) requestEmailChange(ctx context.Context, user *models.User, newEmail string) error {
+	const op = "users.requestEmailChange"
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return nil
+	}
+
+	existing, err := s.usersRepo.GetUserByEmail(ctx, newEmail)
+	if err == nil && existing.ID != user.ID {
+		return fmt.Errorf("%s: %w", op, models.ErrUserAlreadyExists)
+	}
+	if err != nil && !errors.Is(err, models.ErrUserNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	token := generateToken()
+	expiresAt := time.Now().Add(s.emailChangeDur)
+	if err = s.usersRepo.CreateEmailChangeRequest(ctx, user.ID, newEmail, hashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.dispatcher.EmailChangeRequested(ctx, user.Email, newEmail, token, user.Username); err != nil {
+		s.log.Errorw("email change requested but confirmation not sent", "userID", user.ID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("email change requested", "userID", user.ID)
+	return nil
+}
+
+// ConfirmEmailChange применяет новый email по токену из письма
+func (s *UsersService) ConfirmEmailChange(ctx context.Context, req *dto.ConfirmEmailChangeRequest) error {
+	const op = "users.ConfirmEmailChange"
+
+	userID, err := s.usersRepo.ConfirmEmailChange(ctx, hashToken(req.Token))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("email changed", "userID", userID)
+	return nil
+}
+
+// GetProfile возвращает свой профиль вместе с адресом, ожидающим подтверждения
+func (s *UsersService) GetProfile(ctx context.Context, id string) (*dto.UserResp, error) {
+	const op = "users.GetProfile"
+
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.PendingEmail, err = s.usersRepo.GetPendingEmail(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
@@ -1,7 +1,13 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type EventDispatcher interface {
 	UserRegistered(ctx context.Context, email, token, username string) error
+	PasswordResetRequested(ctx context.Context, email, token, username string) error
+	EmailChangeRequested(ctx context.Context, oldEmail, newEmail, token, username string) error
+	AccountLocked(ctx context.Context, email, username string, lockedUntil time.Time, ip string) error
 }
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
 	"time"
@@ -9,18 +11,33 @@ import (
 type Claims struct {
 	UserID string `json:"uid"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
+	// SessionID - сессия, в рамках которой выпущен токен; её отзыв делает токен недействительным
+	SessionID string `json:"sid,omitempty"`
+	// Purpose задан у служебных токенов (например, MFA challenge), которые нельзя использовать как access токен
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (s *UsersService) newToken(user *models.User) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+func newTokenID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+func (s *UsersService) newToken(user *models.User, sessionID string) (string, error) {
+	now := time.Now()
 
-	claims := token.Claims.(jwt.MapClaims)
+	claims := jwt.MapClaims{}
 	claims["uid"] = user.ID
 	claims["email"] = user.Email
-	claims["exp"] = time.Now().Add(s.secretDur).Unix()
+	claims["role"] = user.Role
+	claims["sid"] = sessionID
+	claims["jti"] = newTokenID()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(s.secretDur).Unix()
 
-	tokenString, err := token.SignedString([]byte(s.secret))
+	tokenString, err := s.keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
@@ -28,20 +45,61 @@ func (s *UsersService) newToken(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
-func (a *AuthService) validateToken(tokenString string) (*Claims, error) {
+const purposeMFA = "mfa"
+
+// newChallengeToken выдаёт короткоживущий токен второго шага входа; прав доступа он не даёт
+func (s *UsersService) newChallengeToken(user *models.User) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{}
+	claims["uid"] = user.ID
+	claims["purpose"] = purposeMFA
+	claims["jti"] = newTokenID()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(s.mfa.ChallengeDuration).Unix()
+
+	return s.keys.Sign(claims)
+}
+
+// parseToken проверяет подпись и срок действия access токена, не проверяя отзыв
+func parseToken(tokenString string, keys *KeySet) (*Claims, error) {
+	claims, err := parseClaims(tokenString, keys)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, models.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func parseChallengeToken(tokenString string, keys *KeySet) (*Claims, error) {
+	claims, err := parseClaims(tokenString, keys)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purposeMFA {
+		return nil, models.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func parseClaims(tokenString string, keys *KeySet) (*Claims, error) {
 	claims := &Claims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(a.secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.keyfunc, jwt.WithValidMethods(keys.methods()))
 
 	if err != nil || !token.Valid {
 		return nil, models.ErrInvalidToken
 	}
 
-	if time.Now().After(time.Unix(claims.ExpiresAt.Unix(), 0)) {
+	if claims.ExpiresAt == nil || time.Now().After(claims.ExpiresAt.Time) {
 		return nil, models.ErrTokenExpired
 	}
 
 	return claims, nil
 }
+
+func (a *AuthService) validateToken(tokenString string) (*Claims, error) {
+	return parseToken(tokenString, a.keys)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"math/big"
+	"os"
+	"slices"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+}
+
+// KeySet хранит ключи подписи токенов. Подписывает всегда активный ключ, а проверка идёт
+// по kid из заголовка токена, поэтому при ротации токены, подписанные прежним ключом,
+// остаются валидными, пока ключ не удалён из конфигурации.
+// Без асимметричных ключей используется HS256 с общим секретом (токены без kid).
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+func NewKeySet(secret string) *KeySet {
+	ks := &KeySet{keys: make(map[string]*signingKey)}
+	if secret != "" {
+		ks.keys[""] = &signingKey{
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}
+	}
+	return ks
+}
+
+// LoadKey читает приватный ключ в PEM (PKCS#8, для RSA также PKCS#1)
+func (k *KeySet) LoadKey(id, alg, path string) error {
+	if id == "" {
+		return errors.New("signing key id is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read key %s: %w", id, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("key %s: no PEM data in %s", id, path)
+	}
+
+	var private any
+	if block.Type == "RSA PRIVATE KEY" {
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return fmt.Errorf("parse key %s: %w", id, err)
+	}
+
+	key := &signingKey{id: id, signKey: private}
+	switch alg {
+	case AlgRS256:
+		rsaKey, ok := private.(*rsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("key %s: %s requires an RSA key", id, alg)
+		}
+		key.method = jwt.SigningMethodRS256
+		key.verifyKey = &rsaKey.PublicKey
+	case AlgEdDSA:
+		edKey, ok := private.(ed25519.PrivateKey)
+		if !ok {
+			return fmt.Errorf("key %s: %s requires an Ed25519 key", id, alg)
+		}
+		key.method = jwt.SigningMethodEdDSA
+		key.verifyKey = edKey.Public()
+	default:
+		return fmt.Errorf("key %s: unsupported algorithm %q", id, alg)
+	}
+
+	k.keys[id] = key
+	return nil
+}
+
+// SetActive выбирает ключ для подписи новых токенов; пустой id означает HS256 с общим секретом
+func (k *KeySet) SetActive(id string) error {
+	key, ok := k.keys[id]
+	if !ok {
+		if id == "" {
+			return errors.New("no signing keys configured: set access_secret or active_key_id")
+		}
+		return fmt.Errorf("active signing key %q is not configured", id)
+	}
+	k.active = key
+	return nil
+}
+
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	if k.active.id != "" {
+		token.Header["kid"] = k.active.id
+	}
+	return token.SignedString(k.active.signKey)
+}
+
+// keyfunc выбирает ключ проверки по kid и не даёт подменить алгоритм подписи
+func (k *KeySet) keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+	return key.verifyKey, nil
+}
+
+func (k *KeySet) methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range k.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// sortedKeyIDs возвращает идентификаторы асимметричных ключей
+func (k *KeySet) sortedKeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// JWKS возвращает публичные части асимметричных ключей; общий секрет HS256 не публикуется
+func (k *KeySet) JWKS() dto.JWKSResponse {
+	resp := dto.JWKSResponse{Keys: []dto.JWK{}}
+
+	for _, id := range k.sortedKeyIDs() {
+		key := k.keys[id]
+
+		jwk := dto.JWK{
+			KeyID:     id,
+			Algorithm: key.method.Alg(),
+			Use:       "sig",
+		}
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		resp.Keys = append(resp.Keys, jwk)
+	}
+
+	return resp
+}
+
+// PublicKeys отдаёт публичные ключи в DER для сервисов, проверяющих токены локально
+func (k *KeySet) PublicKeys() ([]models.PublicKey, error) {
+	keys := make([]models.PublicKey, 0, len(k.keys))
+	for _, id := range k.sortedKeyIDs() {
+		key := k.keys[id]
+
+		der, err := x509.MarshalPKIXPublicKey(key.verifyKey)
+		if err != nil {
+			return nil, fmt.Errorf("marshal public key %s: %w", id, err)
+		}
+		keys = append(keys, models.PublicKey{
+			KeyID:     id,
+			Algorithm: key.method.Alg(),
+			DER:       der,
+		})
+	}
+	return keys, nil
+}
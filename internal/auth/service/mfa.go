@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/totp"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodesCount = 10
+	// totpSkew - допустимое расхождение часов в шагах по 30 секунд
+	totpSkew = 1
+)
+
+type MFAConfig struct {
+	Issuer string
+	// EncryptionKey шифрует TOTP-секреты в базе; без него включить 2FA нельзя
+	EncryptionKey     string
+	ChallengeDuration time.Duration
+}
+
+// EnrollTOTP создаёт секрет и ссылку otpauth:// для QR-кода. 2FA включается только после ConfirmTOTP.
+func (s *UsersService) EnrollTOTP(ctx context.Context, userID string) (*dto.TOTPEnrollResponse, error) {
+	const op = "users.EnrollTOTP"
+
+	user, err := s.usersRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	encrypted, err := s.encryptSecret(secret)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.usersRepo.UpsertTOTP(ctx, userID, encrypted); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("totp enrollment started", "userID", userID)
+	return &dto.TOTPEnrollResponse{
+		Secret: secret,
+		URI:    totp.URI(s.mfa.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP включает 2FA по первому коду из приложения и выдаёт коды восстановления.
+// Коды показываются один раз, в базе хранятся только их хеши.
+func (s *UsersService) ConfirmTOTP(ctx context.Context, userID string, req *dto.TOTPConfirmRequest) (*dto.RecoveryCodesResponse, error) {
+	const op = "users.ConfirmTOTP"
+
+	userTOTP, err := s.usersRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if userTOTP.ConfirmedAt != nil {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrMFAAlreadyEnabled)
+	}
+
+	secret, err := s.decryptSecret(userTOTP.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	step, ok := totp.Validate(secret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidMFACode)
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	if err = s.usersRepo.ConfirmTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("totp enabled", "userID", userID)
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP выключает 2FA; нужен пароль и действующий код или код восстановления
+func (s *UsersService) DisableTOTP(ctx context.Context, userID string, req *dto.TOTPDisableRequest) error {
+	const op = "users.DisableTOTP"
+
+	user, err := s.usersRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return fmt.Errorf("%s: %w", op, models.ErrInvalidPassword)
+	}
+
+	if err = s.verifySecondFactor(ctx, userID, req.Code); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.usersRepo.DeleteTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("totp disabled", "userID", userID)
+	return nil
+}
+
+// LoginMFA завершает вход: проверяет challenge токен из Login и код второго фактора
+func (s *UsersService) LoginMFA(ctx context.Context, req *dto.LoginMFARequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	const op = "users.LoginMFA"
+
+	claims, err := parseChallengeToken(req.MFAToken, s.keys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := s.usersRepo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Неверные коды считаются вместе с неверными паролями, иначе код можно перебрать
+	if err = s.checkLoginLocks(ctx, user.ID, client); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.verifySecondFactor(ctx, user.ID, req.Code); err != nil {
+		s.log.Infow("invalid second factor", "userID", user.ID, "ip", client.IP)
+		if errors.Is(err, models.ErrInvalidMFACode) {
+			s.registerLoginFailure(ctx, user, client)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := s.startSession(ctx, user, client)
+	if err != nil {
+		s.log.Errorw("failed to issue tokens", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	s.resetLoginFailures(ctx, user.ID)
+
+	return resp, nil
+}
+
+// mfaEnabled сообщает, включён ли у пользователя подтверждённый второй фактор
+func (s *UsersService) mfaEnabled(ctx context.Context, userID string) (bool, error) {
+	userTOTP, err := s.usersRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMFANotEnabled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return userTOTP.ConfirmedAt != nil, nil
+}
+
+// verifySecondFactor принимает TOTP-код или код восстановления. Каждый TOTP-шаг засчитывается
+// один раз, поэтому перехваченный код нельзя предъявить повторно.
+func (s *UsersService) verifySecondFactor(ctx context.Context, userID, code string) error {
+	userTOTP, err := s.usersRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if userTOTP.ConfirmedAt == nil {
+		return models.ErrMFANotEnabled
+	}
+
+	secret, err := s.decryptSecret(userTOTP.Secret)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := totp.Validate(secret, code, time.Now(), totpSkew); ok {
+		used, err := s.usersRepo.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return models.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.usersRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return models.ErrInvalidMFACode
+	}
+
+	s.log.Infow("recovery code used", "userID", userID)
+	return nil
+}
+
+// generateRecoveryCodes возвращает коды вида xxxxx-xxxxx и их хеши
+func generateRecoveryCodes() (codes, hashes []string) {
+	for range recoveryCodesCount {
+		bytes := make([]byte, 5)
+		rand.Read(bytes)
+		raw := hex.EncodeToString(bytes)
+
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func (s *UsersService) secretCipher() (cipher.AEAD, error) {
+	if s.mfa.EncryptionKey == "" {
+		return nil, errors.New("mfa encryption key is not configured")
+	}
+
+	key := sha256.Sum256([]byte(s.mfa.EncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecret шифрует секрет AES-GCM; nonce хранится перед шифротекстом
+func (s *UsersService) encryptSecret(secret string) (string, error) {
+	aead, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *UsersService) decryptSecret(encrypted string) (string, error) {
+	aead, err := s.secretCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("decode secret: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	secret, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret: %w", err)
+	}
+	return string(secret), nil
+}
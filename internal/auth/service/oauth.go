@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/oauth"
+	"strings"
+	"time"
+)
+
+// OAuthProvider - внешний провайдер входа по authorization code flow с PKCE
+type OAuthProvider interface {
+	AuthCodeURL(ctx context.Context, state, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier string) (*oauth.Identity, error)
+}
+
+type OAuthConfig struct {
+	// Providers - включённые провайдеры по имени из URL (/auth/oauth/{provider})
+	Providers     map[string]OAuthProvider
+	StateDuration time.Duration
+}
+
+const (
+	// maxUsernameLen оставляет место для суффикса при совпадении имён (username VARCHAR(50))
+	maxUsernameLen     = 40
+	usernameRetryCount = 3
+)
+
+func (s *UsersService) oauthProvider(name string) (OAuthProvider, error) {
+	provider, ok := s.oauth.Providers[name]
+	if !ok {
+		return nil, models.ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// StartOAuth начинает вход через провайдера и возвращает адрес, на который нужно перенаправить пользователя.
+// StateBinding кладётся в cookie браузера: без неё чужой callback с тем же state не примется.
+func (s *UsersService) StartOAuth(ctx context.Context, providerName string) (*dto.OAuthStart, error) {
+	const op = "users.StartOAuth"
+
+	provider, err := s.oauthProvider(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	verifier, err := oauth.NewCodeVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	state := generateToken()
+	expiresAt := time.Now().Add(s.oauth.StateDuration)
+
+	err = s.usersRepo.CreateOAuthState(ctx, hashToken(state), providerName, verifier, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, oauth.CodeChallenge(verifier))
+	if err != nil {
+		s.log.Errorw("failed to build authorization url", "provider", providerName, "error", err)
+		return nil, fmt.Errorf("%s: %w: %w", op, models.ErrProviderFailed, err)
+	}
+
+	return &dto.OAuthStart{
+		AuthURL:      authURL,
+		StateBinding: hashToken(state),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// CompleteOAuth обрабатывает возврат от провайдера: проверяет state и его привязку к браузеру,
+// обменивает код и входит под привязанным, найденным по email или новым пользователем.
+// Без проверки привязки злоумышленник мог бы подсунуть жертве свой callback и войти её браузером в свой аккаунт.
+func (s *UsersService) CompleteOAuth(ctx context.Context, providerName, state, stateBinding, code string, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	const op = "users.CompleteOAuth"
+
+	provider, err := s.oauthProvider(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(state)), []byte(stateBinding)) != 1 {
+		s.log.Warnw("oauth state is not bound to this browser", "provider", providerName, "ip", client.IP)
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidToken)
+	}
+
+	verifier, err := s.usersRepo.ConsumeOAuthState(ctx, hashToken(state), providerName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	identity, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		s.log.Errorw("oauth code exchange failed", "provider", providerName, "error", err)
+		return nil, fmt.Errorf("%s: %w: %w", op, models.ErrProviderFailed, err)
+	}
+
+	user, err := s.oauthUser(ctx, providerName, identity)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := s.completeLogin(ctx, user, client)
+	if err != nil {
+		s.log.Errorw("failed to issue tokens", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// oauthUser находит пользователя по привязке к провайдеру. Без привязки аккаунт связывается
+// с пользователем с тем же email или создаётся новый - только если провайдер подтвердил email.
+func (s *UsersService) oauthUser(ctx context.Context, providerName string, identity *oauth.Identity) (*models.User, error) {
+	user, err := s.usersRepo.GetUserByIdentity(ctx, providerName, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, models.ErrUserNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, models.ErrEmailNotVerified
+	}
+
+	user, err = s.usersRepo.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		return user, s.linkIdentity(ctx, user, providerName, identity)
+	case errors.Is(err, models.ErrUserNotFound):
+		return s.createOAuthUser(ctx, providerName, identity)
+	default:
+		return nil, err
+	}
+}
+
+func (s *UsersService) linkIdentity(ctx context.Context, user *models.User, providerName string, identity *oauth.Identity) error {
+	// Неподтверждённый локальный аккаунт мог зарегистрировать кто угодно. Владелец адреса
+	// подтвердил его у провайдера, поэтому аккаунт подтверждается, чужой пароль сбрасывается,
+	// а токены, сессии и API ключи прежнего владельца отзываются
+	if user.Role == roleUnverified {
+		if err := s.usersRepo.ClaimUnverifiedUser(ctx, user, providerName, identity.Subject, identity.Email); err != nil {
+			return err
+		}
+		s.log.Warnw("unverified account claimed via oauth, password reset and credentials revoked",
+			"userID", user.ID, "provider", providerName)
+		return nil
+	}
+
+	if err := s.usersRepo.LinkIdentity(ctx, user.ID, providerName, identity.Subject, identity.Email); err != nil {
+		return err
+	}
+
+	s.log.Infow("oauth identity linked", "userID", user.ID, "provider", providerName)
+	return nil
+}
+
+// createOAuthUser регистрирует пользователя без пароля: войти по паролю он сможет после сброса пароля
+func (s *UsersService) createOAuthUser(ctx context.Context, providerName string, identity *oauth.Identity) (*models.User, error) {
+	username := oauthUsername(identity)
+
+	for attempt := range usernameRetryCount {
+		user := &models.User{
+			Username: username,
+			Email:    identity.Email,
+			Role:     roleUser,
+		}
+		if attempt > 0 {
+			user.Username = fmt.Sprintf("%s-%s", username, generateToken()[:6])
+		}
+
+		err := s.usersRepo.CreateOAuthUser(ctx, user, providerName, identity.Subject)
+		if err == nil {
+			s.log.Infow("user registered via oauth", "userID", user.ID, "provider", providerName)
+			return user, nil
+		}
+		if !errors.Is(err, models.ErrUserAlreadyExists) {
+			return nil, err
+		}
+	}
+
+	return nil, models.ErrUserAlreadyExists
+}
+
+func oauthUsername(identity *oauth.Identity) string {
+	username := identity.Username
+	if username == "" {
+		username, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	username = strings.Join(strings.Fields(username), "_")
+	if len(username) > maxUsernameLen {
+		username = username[:maxUsernameLen]
+	}
+	return strings.ToValidUTF8(username, "")
+}
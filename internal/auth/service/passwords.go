@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+const (
+	// resetIPLimit - сколько писем сброса пароля можно запросить с одного IP за resetWindow,
+	// resetUserLimit - сколько писем может получить один пользователь за то же время
+	resetIPLimit   = 10
+	resetUserLimit = 3
+	resetWindow    = time.Hour
+)
+
+// ForgotPassword отправляет письмо со ссылкой для сброса пароля.
+// Ответ не зависит от того, существует ли аккаунт: неизвестный email и превышенный лимит писем
+// пользователю молча пропускаются, а ErrTooManyRequests возвращается только по лимиту IP.
+func (s *UsersService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest, client dto.ClientInfo) error {
+	const op = "users.ForgotPassword"
+
+	if client.IP != "" {
+		limited, err := s.overLimit(ctx, throttleScopeResetIP, client.IP, resetIPLimit, resetWindow)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if limited {
+			s.log.Infow("password reset rate limited", "ip", client.IP)
+			return fmt.Errorf("%s: %w", op, models.ErrTooManyRequests)
+		}
+	}
+
+	user, err := s.usersRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			s.log.Infow("password reset requested for unknown email", "email", req.Email)
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Лимит на пользователя не даёт завалить его письмами с разных адресов
+	limited, err := s.overLimit(ctx, throttleScopeReset, user.ID, resetUserLimit, resetWindow)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if limited {
+		s.log.Infow("password reset requested too often", "userID", user.ID)
+		return nil
+	}
+
+	token := generateToken()
+	expiresAt := time.Now().Add(s.resetDur)
+	if err = s.usersRepo.CreatePasswordResetToken(ctx, user.ID, hashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Ошибка отправки не возвращается: иначе по ответу 500 было бы видно, что аккаунт существует
+	if err = s.dispatcher.PasswordResetRequested(ctx, user.Email, token, user.Username); err != nil {
+		s.log.Errorw("password reset token created but email not sent", "userID", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// ResetPassword меняет пароль по одноразовому токену и завершает все сессии пользователя
+func (s *UsersService) ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error {
+	const op = "users.ResetPassword"
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		s.log.Errorw("generating password hash", "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := s.usersRepo.ResetPassword(ctx, hashToken(req.Token), string(passHash))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.tokensRepo.RevokeUserTokens(ctx, userID); err != nil {
+		s.log.Errorw("password reset but sessions not revoked", "userID", userID, "error", err)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("password reset", "userID", userID)
+	return nil
+}
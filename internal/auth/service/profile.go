@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UpdateProfile меняет профиль самого пользователя. Смена email или пароля требует текущего пароля,
+// новый email применяется после подтверждения, а после смены пароля все сессии завершаются, как и при сбросе.
+// Роль здесь не меняется.
+func (s *UsersService) UpdateProfile(ctx context.Context, id string, req *dto.ProfileUpdateRequest) (*dto.UserResp, error) {
+	const op = "users.UpdateProfile"
+	s.log.Infow("updating profile", "userID", id)
+
+	user, err := s.usersRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if req.Email != nil || req.Password != nil {
+		if req.CurrentPassword == nil || *req.CurrentPassword == "" {
+			return nil, fmt.Errorf("%s: %w", op, models.ErrPasswordRequired)
+		}
+		if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(*req.CurrentPassword)); err != nil {
+			s.log.Infow("invalid current password on profile update", "userID", id)
+			return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidPassword)
+		}
+	}
+
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.Password != nil {
+		passHash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			s.log.Errorw("generating password hash", "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		user.PasswordHash = string(passHash)
+	}
+
+	if err = s.usersRepo.UpdateUser(ctx, user); err != nil {
+		s.log.Errorw("failed to update profile", "userID", id, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if req.Password != nil {
+		if err = s.tokensRepo.RevokeUserTokens(ctx, id); err != nil {
+			s.log.Errorw("password changed but sessions not revoked", "userID", id, "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if req.Email != nil {
+		if err = s.requestEmailChange(ctx, user, *req.Email); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return s.GetProfile(ctx, id)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RevocationChecker interface {
+	IsAccessTokenRevoked(ctx context.Context, jti, userID, sessionID string, issuedAt time.Time) (bool, error)
+}
+
+// revokedCache запоминает отозванные токены до их истечения, чтобы повторные запросы
+// с украденным токеном не доходили до базы. Отзыв необратим, поэтому кешировать его безопасно.
+type revokedCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func newRevokedCache() *revokedCache {
+	return &revokedCache{entries: make(map[string]time.Time)}
+}
+
+func (c *revokedCache) Has(jti string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt, ok := c.entries[jti]
+	if ok && time.Now().After(expiresAt) {
+		delete(c.entries, jti)
+		return false
+	}
+	return ok
+}
+
+func (c *revokedCache) Add(jti string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, exp := range c.entries {
+		if now.After(exp) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[jti] = expiresAt
+}
+
+// isRevoked проверяет, не отозван ли токен logout'ом, отзывом его сессии или выходом из всех сессий
+func (a *AuthService) isRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if claims.ID != "" && a.revoked.Has(claims.ID) {
+		return true, nil
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	revoked, err := a.revocations.IsAccessTokenRevoked(ctx, claims.ID, claims.UserID, claims.SessionID, issuedAt)
+	if err != nil {
+		return false, err
+	}
+	if revoked && claims.ID != "" {
+		a.revoked.Add(claims.ID, claims.ExpiresAt.Time)
+	}
+	return revoked, nil
+}
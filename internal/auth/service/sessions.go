@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+)
+
+// ListSessions возвращает активные сессии пользователя; сессия текущего запроса помечается Current
+func (s *UsersService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.SessionResp, error) {
+	const op = "users.ListSessions"
+
+	sessions, err := s.tokensRepo.ListSessions(ctx, userID)
+	if err != nil {
+		s.log.Errorw("failed to list sessions", "userID", userID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessionsResp := make([]*dto.SessionResp, 0, len(sessions))
+	for _, session := range sessions {
+		sessionsResp = append(sessionsResp, &dto.SessionResp{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    currentSessionID != "" && session.ID == currentSessionID,
+		})
+	}
+
+	return sessionsResp, nil
+}
+
+// RevokeSession завершает одну из сессий пользователя: её refresh токены отзываются,
+// а выпущенные в ней access токены перестают проходить проверку
+func (s *UsersService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	const op = "users.RevokeSession"
+
+	if err := s.tokensRepo.RevokeSession(ctx, sessionID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Infow("session revoked", "userID", userID, "sessionID", sessionID)
+	return nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+const (
+	throttleScopeUser     = "user"
+	throttleScopeIP       = "ip"
+	throttleScopeResendIP = "resend_ip"
+	throttleScopeResetIP  = "reset_ip"
+	throttleScopeReset    = "reset_user"
+)
+
+type LoginThrottler interface {
+	GetLockedUntil(ctx context.Context, scope, subject string) (*time.Time, error)
+	RegisterFailure(ctx context.Context, scope, subject string, window time.Duration) (int, error)
+	Lock(ctx context.Context, scope, subject string, until time.Time) error
+	Reset(ctx context.Context, scope, subject string, window time.Duration) error
+}
+
+// LockoutConfig задаёт защиту от подбора пароля. Первые FreeAttempts ошибок не ограничиваются,
+// дальше вход закрывается на BaseDelay, удваивая паузу с каждой ошибкой до MaxDelay.
+// После LockThreshold ошибок аккаунт блокируется на LockDuration и владельцу уходит письмо.
+type LockoutConfig struct {
+	Window         time.Duration
+	FreeAttempts   int
+	IPFreeAttempts int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	LockThreshold  int
+	LockDuration   time.Duration
+}
+
+func (c LockoutConfig) backoff(failures, free int) time.Duration {
+	if failures <= free {
+		return 0
+	}
+
+	delay := c.BaseDelay
+	for i := free + 1; i < failures && delay < c.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, c.MaxDelay)
+}
+
+// checkLoginLocks отклоняет попытку входа, пока действует блокировка аккаунта или IP.
+// Проверка идёт до сравнения пароля, чтобы во время блокировки подбор ничего не давал.
+func (s *UsersService) checkLoginLocks(ctx context.Context, userID string, client dto.ClientInfo) error {
+	checks := [][2]string{{throttleScopeIP, client.IP}}
+	if userID != "" {
+		checks = append(checks, [2]string{throttleScopeUser, userID})
+	}
+
+	for _, check := range checks {
+		if check[1] == "" {
+			continue
+		}
+		lockedUntil, err := s.throttler.GetLockedUntil(ctx, check[0], check[1])
+		if err != nil {
+			return err
+		}
+		if lockedUntil != nil {
+			return &models.AccountLockedError{Until: *lockedUntil}
+		}
+	}
+	return nil
+}
+
+// registerLoginFailure учитывает неудачный вход для IP и, если аккаунт известен, для пользователя.
+// Ошибки учёта только логируются: ответ клиенту всё равно "неверные данные".
+func (s *UsersService) registerLoginFailure(ctx context.Context, user *models.User, client dto.ClientInfo) {
+	now := time.Now()
+
+	if client.IP != "" {
+		failures, err := s.throttler.RegisterFailure(ctx, throttleScopeIP, client.IP, s.lockout.Window)
+		if err != nil {
+			s.log.Errorw("failed to register login failure", "ip", client.IP, "error", err)
+		} else if delay := s.lockout.backoff(failures, s.lockout.IPFreeAttempts); delay > 0 {
+			if err = s.throttler.Lock(ctx, throttleScopeIP, client.IP, now.Add(delay)); err != nil {
+				s.log.Errorw("failed to throttle ip", "ip", client.IP, "error", err)
+			}
+		}
+	}
+
+	if user == nil {
+		return
+	}
+
+	failures, err := s.throttler.RegisterFailure(ctx, throttleScopeUser, user.ID, s.lockout.Window)
+	if err != nil {
+		s.log.Errorw("failed to register login failure", "userID", user.ID, "error", err)
+		return
+	}
+
+	delay := s.lockout.backoff(failures, s.lockout.FreeAttempts)
+	locked := failures >= s.lockout.LockThreshold
+	if locked {
+		delay = max(delay, s.lockout.LockDuration)
+	}
+	if delay == 0 {
+		return
+	}
+
+	until := now.Add(delay)
+	if err = s.throttler.Lock(ctx, throttleScopeUser, user.ID, until); err != nil {
+		s.log.Errorw("failed to throttle user", "userID", user.ID, "error", err)
+		return
+	}
+
+	if locked {
+		s.log.Warnw("account locked after failed logins", "userID", user.ID, "failures", failures, "ip", client.IP)
+		if err = s.dispatcher.AccountLocked(ctx, user.Email, user.Username, until, client.IP); err != nil {
+			s.log.Errorw("account locked but email not sent", "userID", user.ID, "error", err)
+		}
+	}
+}
+
+// overLimit учитывает запрос в счётчике scope/subject и сообщает, превышен ли limit за window.
+// Счётчики неудачных входов переиспользуются для лимитов на отправку писем.
+func (s *UsersService) overLimit(ctx context.Context, scope, subject string, limit int, window time.Duration) (bool, error) {
+	requests, err := s.throttler.RegisterFailure(ctx, scope, subject, window)
+	if err != nil {
+		return false, err
+	}
+	return requests > limit, nil
+}
+
+func (s *UsersService) resetLoginFailures(ctx context.Context, userID string) {
+	if err := s.throttler.Reset(ctx, throttleScopeUser, userID, s.lockout.Window); err != nil {
+		s.log.Errorw("failed to reset login failures", "userID", userID, "error", err)
+	}
+}
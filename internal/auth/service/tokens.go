@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+type TokensRepository interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id string) (bool, error)
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	RevokeRefreshToken(ctx context.Context, hash, userID string) error
+	RevokeAccessToken(ctx context.Context, jti, userID string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID string) error
+	CreateSession(ctx context.Context, session *models.Session) error
+	TouchSession(ctx context.Context, id, ip, userAgent string) (bool, error)
+	ListSessions(ctx context.Context, userID string) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, id, userID string) error
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id, userID string) error
+}
+
+// hashToken - в базе хранятся только хеши непрозрачных токенов
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession открывает сессию для нового входа и выдаёт её первую пару токенов
+func (s *UsersService) startSession(ctx context.Context, user *models.User, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	session := &models.Session{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+	}
+	if err := s.tokensRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, session.ID)
+}
+
+// issueTokens выдаёт пару access/refresh токенов сессии; семейство refresh токенов совпадает с сессией
+func (s *UsersService) issueTokens(ctx context.Context, user *models.User, sessionID string) (*dto.LoginResponse, error) {
+	accessToken, err := s.newToken(user, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("generate access token: %w", err)
+	}
+
+	refreshToken := generateToken()
+	err = s.tokensRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshDur),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save refresh token: %w", err)
+	}
+
+	return &dto.LoginResponse{
+		Token:            accessToken,
+		Type:             "Bearer",
+		ExpiresIn:        int64(s.secretDur.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(s.refreshDur.Seconds()),
+	}, nil
+}
+
+// Refresh обменивает refresh токен на новую пару токенов. Каждый refresh токен одноразовый:
+// повторное предъявление означает, что токен утёк, поэтому отзывается всё семейство.
+func (s *UsersService) Refresh(ctx context.Context, req *dto.RefreshRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	const op = "users.Refresh"
+
+	token, err := s.tokensRepo.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if token.RevokedAt != nil {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidToken)
+	}
+	if token.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, op, token)
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrTokenExpired)
+	}
+
+	marked, err := s.tokensRepo.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !marked {
+		return nil, s.revokeReusedFamily(ctx, op, token)
+	}
+
+	active, err := s.tokensRepo.TouchSession(ctx, token.FamilyID, client.IP, client.UserAgent)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if !active {
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidToken)
+	}
+
+	user, err := s.usersRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := s.issueTokens(ctx, user, token.FamilyID)
+	if err != nil {
+		s.log.Errorw("failed to issue tokens", "userID", user.ID, "error", err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+func (s *UsersService) revokeReusedFamily(ctx context.Context, op string, token *models.RefreshToken) error {
+	s.log.Warnw("refresh token reuse detected, revoking family",
+		"userID", token.UserID,
+		"familyID", token.FamilyID)
+
+	if err := s.tokensRepo.RevokeTokenFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return fmt.Errorf("%s: %w", op, models.ErrTokenReused)
+}
+
+// Logout отзывает предъявленный access токен, его сессию и, если передан, семейство refresh токена.
+// С флагом All отзываются все токены пользователя, выпущенные до этого момента.
+func (s *UsersService) Logout(ctx context.Context, accessToken string, req *dto.LogoutRequest) error {
+	const op = "users.Logout"
+
+	claims, err := parseToken(accessToken, s.keys)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if req.All {
+		if err = s.tokensRepo.RevokeUserTokens(ctx, claims.UserID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		s.log.Infow("user logged out from all sessions", "userID", claims.UserID)
+		return nil
+	}
+
+	if claims.ID != "" {
+		if err = s.tokensRepo.RevokeAccessToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if claims.SessionID != "" {
+		err = s.tokensRepo.RevokeSession(ctx, claims.SessionID, claims.UserID)
+		if err != nil && !errors.Is(err, models.ErrSessionNotFound) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if req.RefreshToken != "" {
+		if err = s.tokensRepo.RevokeRefreshToken(ctx, hashToken(req.RefreshToken), claims.UserID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	s.log.Infow("user logged out", "userID", claims.UserID)
+	return nil
+}
@@ -21,29 +21,77 @@ type UsersRepository interface {
 	UpdateUser(ctx context.Context, user *models.User) error
 	DeleteUser(ctx context.Context, id string) error
 	FindByVerificationToken(ctx context.Context, token string) (*models.User, error)
+	CreatePasswordResetToken(ctx context.Context, userID, hash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, hash, passwordHash string) (string, error)
+	CreateEmailChangeRequest(ctx context.Context, userID, newEmail, hash string, expiresAt time.Time) error
+	GetPendingEmail(ctx context.Context, userID string) (string, error)
+	ConfirmEmailChange(ctx context.Context, hash string) (string, error)
+	SetVerificationToken(ctx context.Context, userID, hash string, expiresAt time.Time, minInterval time.Duration) (bool, error)
+	UpsertTOTP(ctx context.Context, userID, secret string) error
+	GetTOTP(ctx context.Context, userID string) (*models.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID string, step int64, codeHashes []string) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error)
+	DeleteTOTP(ctx context.Context, userID string) error
+	CreateOAuthState(ctx context.Context, hash, provider, codeVerifier string, expiresAt time.Time) error
+	ConsumeOAuthState(ctx context.Context, hash, provider string) (string, error)
+	GetUserByIdentity(ctx context.Context, provider, subject string) (*models.User, error)
+	LinkIdentity(ctx context.Context, userID, provider, subject, email string) error
+	ClaimUnverifiedUser(ctx context.Context, user *models.User, provider, subject, email string) error
+	CreateOAuthUser(ctx context.Context, user *models.User, provider, subject string) error
 }
 
 type UsersService struct {
-	usersRepo  UsersRepository
-	dispatcher EventDispatcher
-	log        *zap.SugaredLogger
-	secret     string
-	secretDur  time.Duration
+	usersRepo      UsersRepository
+	tokensRepo     TokensRepository
+	throttler      LoginThrottler
+	dispatcher     EventDispatcher
+	log            *zap.SugaredLogger
+	keys           *KeySet
+	secretDur      time.Duration
+	refreshDur     time.Duration
+	resetDur       time.Duration
+	emailChangeDur time.Duration
+	verifyDur      time.Duration
+	resendInterval time.Duration
+	mfa            MFAConfig
+	lockout        LockoutConfig
+	oauth          OAuthConfig
 }
 
 func NewUsersService(
 	usersRepo UsersRepository,
+	tokensRepo TokensRepository,
+	throttler LoginThrottler,
 	dispatcher EventDispatcher,
 	logger *zap.SugaredLogger,
-	secret string,
+	keys *KeySet,
 	dur time.Duration,
+	refreshDur time.Duration,
+	resetDur time.Duration,
+	emailChangeDur time.Duration,
+	verifyDur time.Duration,
+	resendInterval time.Duration,
+	mfa MFAConfig,
+	lockout LockoutConfig,
+	oauth OAuthConfig,
 ) *UsersService {
 	return &UsersService{
-		usersRepo:  usersRepo,
-		dispatcher: dispatcher,
-		log:        logger,
-		secret:     secret,
-		secretDur:  dur,
+		usersRepo:      usersRepo,
+		tokensRepo:     tokensRepo,
+		throttler:      throttler,
+		dispatcher:     dispatcher,
+		log:            logger,
+		keys:           keys,
+		secretDur:      dur,
+		refreshDur:     refreshDur,
+		resetDur:       resetDur,
+		emailChangeDur: emailChangeDur,
+		verifyDur:      verifyDur,
+		resendInterval: resendInterval,
+		mfa:            mfa,
+		lockout:        lockout,
+		oauth:          oauth,
 	}
 }
 
@@ -65,12 +113,14 @@ func (s *UsersService) Register(ctx context.Context, userReq *dto.UserCreateRequ
 	}
 
 	verificationToken := generateToken()
+	expiresAt := time.Now().Add(s.verifyDur)
 
 	user := &models.User{
-		Username:          userReq.Username,
-		Email:             userReq.Email,
-		PasswordHash:      string(passHash),
-		VerificationToken: verificationToken,
+		Username:              userReq.Username,
+		Email:                 userReq.Email,
+		PasswordHash:          string(passHash),
+		VerificationToken:     hashToken(verificationToken),
+		VerificationExpiresAt: &expiresAt,
 	}
 	err = s.usersRepo.CreateUser(ctx, user)
 
@@ -78,28 +128,16 @@ func (s *UsersService) Register(ctx context.Context, userReq *dto.UserCreateRequ
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.dispatcher.UserRegistered(ctx, user.Email, user.VerificationToken, user.Username)
+	// Пользователь уже создан, поэтому ошибка отправки не возвращается: письмо можно запросить повторно
+	err = s.dispatcher.UserRegistered(ctx, user.Email, verificationToken, user.Username)
 	if err != nil {
 		s.log.Errorw("user created but email not sent", "UserId", user.ID, "error", err)
-		return user.ID, fmt.Errorf("user created but email not sent: %w", err)
 	}
 
 	return user.ID, nil
 
 }
 
-func (s *UsersService) VerifyEmail(ctx context.Context, token string) error {
-	user, err := s.usersRepo.FindByVerificationToken(ctx, token)
-	if err != nil {
-		return fmt.Errorf("failed to find user with token %s: %w", token, err)
-	}
-
-	user.Role = "user"
-	user.VerificationToken = ""
-
-	return s.usersRepo.UpdateUser(ctx, user)
-}
-
 func (s *UsersService) GetUser(ctx context.Context, id string) (*dto.UserResp, error) {
 	const op = "users.GetUser"
 	s.log.Infow("getting user", "userID", id)
@@ -125,36 +163,69 @@ func (s *UsersService) GetUser(ctx context.Context, id string) (*dto.UserResp, e
 	return userResp, nil
 }
 
-func (s *UsersService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error) {
-	const op = "users.GetUser"
-	s.log.Infow("getting user", "email", req.Email)
+func (s *UsersService) Login(ctx context.Context, req *dto.LoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	const op = "users.Login"
+	s.log.Infow("logging in", "email", req.Email, "ip", client.IP)
 
 	user, err := s.usersRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		s.log.Errorw("failed to get user", "email", req.Email, "error", err)
+		if !errors.Is(err, models.ErrUserNotFound) {
+			s.log.Errorw("failed to get user", "email", req.Email, "error", err)
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		// Попытки с несуществующими адресами тоже засчитываются IP
+		if err = s.checkLoginLocks(ctx, "", client); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		s.registerLoginFailure(ctx, nil, client)
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidCredentials)
+	}
+
+	if err = s.checkLoginLocks(ctx, user.ID, client); err != nil {
+		s.log.Infow("login rejected: locked", "userID", user.ID, "ip", client.IP, "error", err)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		s.log.Infow("invalid credentials", "error", err)
-		return nil, fmt.Errorf("%s: invalid credentials", op)
+		s.log.Infow("invalid credentials", "userID", user.ID, "ip", client.IP)
+		s.registerLoginFailure(ctx, user, client)
+		return nil, fmt.Errorf("%s: %w", op, models.ErrInvalidCredentials)
 	}
 
-	token, err := s.newToken(user)
+	resp, err := s.completeLogin(ctx, user, client)
 	if err != nil {
-		s.log.Errorw("failed to generate token", "email", req.Email, "error", err)
+		s.log.Errorw("failed to issue tokens", "email", req.Email, "error", err)
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
-
-	resp := &dto.LoginResponse{
-		Token:     token,
-		Type:      "Bearer",
-		ExpiresIn: int64(s.secretDur.Seconds()),
+	if !resp.MFARequired {
+		s.resetLoginFailures(ctx, user.ID)
 	}
 
 	return resp, nil
 }
 
+// completeLogin завершает вход после проверки первого фактора. При включённой 2FA вместо токенов
+// выдаётся challenge для POST /auth/login/mfa.
+func (s *UsersService) completeLogin(ctx context.Context, user *models.User, client dto.ClientInfo) (*dto.LoginResponse, error) {
+	mfaEnabled, err := s.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		challenge, err := s.newChallengeToken(user)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.LoginResponse{
+			MFARequired: true,
+			MFAToken:    challenge,
+			ExpiresIn:   int64(s.mfa.ChallengeDuration.Seconds()),
+		}, nil
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
 func (s *UsersService) ListUsers(ctx context.Context) ([]*dto.UserResp, error) {
 	const op = "users.ListUsers"
 	s.log.Info("listing all users")
@@ -196,9 +267,6 @@ func (s *UsersService) UpdateUser(ctx context.Context, id string, userReq *dto.U
 	if userReq.Username != nil {
 		existingUser.Username = *userReq.Username
 	}
-	if userReq.Email != nil {
-		existingUser.Email = *userReq.Email
-	}
 	if userReq.Password != nil {
 		passHash, err := bcrypt.GenerateFromPassword([]byte(*userReq.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -207,6 +275,7 @@ func (s *UsersService) UpdateUser(ctx context.Context, id string, userReq *dto.U
 		}
 		existingUser.PasswordHash = string(passHash)
 	}
+	roleChanged := userReq.Role != nil && *userReq.Role != existingUser.Role
 	if userReq.Role != nil {
 		existingUser.Role = *userReq.Role
 	}
@@ -217,6 +286,21 @@ func (s *UsersService) UpdateUser(ctx context.Context, id string, userReq *dto.U
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	// Роль зашита в выданные токены, а старый пароль мог быть скомпрометирован - сессии нужно завершить
+	if userReq.Password != nil || roleChanged {
+		if err = s.tokensRepo.RevokeUserTokens(ctx, id); err != nil {
+			s.log.Errorw("user updated but sessions not revoked", "userID", id, "error", err)
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Новый адрес вступает в силу только после подтверждения владельцем
+	if userReq.Email != nil {
+		if err = s.requestEmailChange(ctx, existingUser, *userReq.Email); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"time"
+)
+
+const (
+	// resendIPLimit - сколько писем подтверждения можно запросить с одного IP за resendIPWindow
+	resendIPLimit  = 10
+	resendIPWindow = time.Hour
+)
+
+func (s *UsersService) VerifyEmail(ctx context.Context, token string) error {
+	const op = "users.VerifyEmail"
+
+	user, err := s.usersRepo.FindByVerificationToken(ctx, hashToken(token))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.VerificationExpiresAt == nil || time.Now().After(*user.VerificationExpiresAt) {
+		return fmt.Errorf("%s: %w", op, models.ErrTokenExpired)
+	}
+
+	// Подтверждение не должно понижать роль администратора
+	if user.Role == roleUnverified {
+		user.Role = roleUser
+	}
+	user.VerificationToken = ""
+
+	return s.usersRepo.UpdateUser(ctx, user)
+}
+
+// ResendVerification выпускает новый токен подтверждения и отправляет письмо повторно.
+// Ответ не зависит от того, существует ли аккаунт: неизвестный, подтверждённый и недавно
+// получивший письмо email молча пропускаются, а ErrTooManyRequests возвращается только по лимиту IP.
+func (s *UsersService) ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest, client dto.ClientInfo) error {
+	const op = "users.ResendVerification"
+
+	// Запросы с IP считаются до поиска пользователя, чтобы лимит действовал одинаково для любых адресов
+	if client.IP != "" {
+		limited, err := s.overLimit(ctx, throttleScopeResendIP, client.IP, resendIPLimit, resendIPWindow)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if limited {
+			s.log.Infow("verification resend rate limited", "ip", client.IP)
+			return fmt.Errorf("%s: %w", op, models.ErrTooManyRequests)
+		}
+	}
+
+	user, err := s.usersRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			s.log.Infow("verification resend requested for unknown email", "email", req.Email)
+			return nil
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.VerificationToken == "" {
+		s.log.Infow("verification resend requested for verified user", "userID", user.ID)
+		return nil
+	}
+
+	token := generateToken()
+	expiresAt := time.Now().Add(s.verifyDur)
+	issued, err := s.usersRepo.SetVerificationToken(ctx, user.ID, hashToken(token), expiresAt, s.resendInterval)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !issued {
+		s.log.Infow("verification resend requested too often", "userID", user.ID)
+		return nil
+	}
+
+	// Ошибка отправки не возвращается: иначе по ответу 500 было бы видно, что аккаунт существует
+	if err = s.dispatcher.UserRegistered(ctx, user.Email, token, user.Username); err != nil {
+		s.log.Errorw("verification token reissued but email not sent", "userID", user.ID, "error", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret - ключ "12345678901234567890" из RFC 6238, приложение B, в base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Векторы RFC 6238 для SHA1, усечённые до 6 цифр (последние цифры 8-значного кода)
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, tt := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.code {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		skew   int
+		ok     bool
+	}{
+		{name: "current step", offset: 0, skew: 0, ok: true},
+		{name: "previous step without skew", offset: -1, skew: 0, ok: false},
+		{name: "previous step within skew", offset: -1, skew: 1, ok: true},
+		{name: "next step within skew", offset: 1, skew: 1, ok: true},
+		{name: "two steps back outside skew", offset: -2, skew: 1, ok: false},
+		{name: "two steps ahead outside skew", offset: 2, skew: 1, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+
+			step, ok := Validate(rfcSecret, code, now, tt.skew)
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("Validate step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{name: "lowercase secret", secret: strings.ToLower(rfcSecret), code: "287082", ok: true},
+		{name: "mixed case secret", secret: "gezdgnbvGY3TQOJQgezdgnbvGY3TQOJQ", code: "287082", ok: true},
+		{name: "code with spaces", secret: rfcSecret, code: "287 082", ok: true},
+		{name: "wrong code", secret: rfcSecret, code: "287083", ok: false},
+		{name: "short code", secret: rfcSecret, code: "28708", ok: false},
+		{name: "invalid secret", secret: "not base32!", code: "287082", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(tt.secret, tt.code, now, 0); ok != tt.ok {
+				t.Errorf("Validate(%q, %q) ok = %v, want %v", tt.secret, tt.code, ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	if _, err = Code(secret, 0); err != nil {
+		t.Errorf("generated secret %q is not valid base32: %v", secret, err)
+	}
+}
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"errors"
+	"github.com/go-playground/validator/v10"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
 	authv1 "github.com/mSulimenko/dev-blog-platform/protos/gen/go"
 	"google.golang.org/grpc"
@@ -11,22 +12,46 @@ import (
 )
 
 type AuthService interface {
-	Auth(ctx context.Context, token string) (userId, role string, err error)
+	Auth(ctx context.Context, token string) (userId, role, sessionID string, err error)
+	AuthAPIKey(ctx context.Context, key string) (userId, role string, scopes []string, err error)
+	GetUser(ctx context.Context, id string) (*models.User, error)
+	GetUsers(ctx context.Context, ids []string) ([]*models.User, error)
+	CheckPermission(ctx context.Context, userID, permission string) (bool, string, error)
+}
+
+// maxBatchUsers ограничивает размер BatchGetUsers; больше id за раз клиенту не нужно
+const maxBatchUsers = 100
+
+var validate = validator.New()
+
+type KeysProvider interface {
+	PublicKeys() ([]models.PublicKey, error)
 }
 
 type serverAPI struct {
 	authv1.UnimplementedAuthServer
 	authService AuthService
+	keys        KeysProvider
 }
 
-func Register(gRPCServer *grpc.Server, auth AuthService) {
-	authv1.RegisterAuthServer(gRPCServer, &serverAPI{authService: auth})
+func Register(gRPCServer *grpc.Server, auth AuthService, keys KeysProvider) {
+	authv1.RegisterAuthServer(gRPCServer, &serverAPI{authService: auth, keys: keys})
 }
 
 func (s *serverAPI) Validate(ctx context.Context, in *authv1.ValidateRequest,
 ) (*authv1.ValidateResponse, error) {
 
-	userId, role, err := s.authService.Auth(ctx, in.Token)
+	var (
+		userId, role string
+		scopes       []string
+		err          error
+	)
+	// Запрос с api_key проверяет API ключ, иначе - access токен
+	if in.ApiKey != "" {
+		userId, role, scopes, err = s.authService.AuthAPIKey(ctx, in.ApiKey)
+	} else {
+		userId, role, _, err = s.authService.Auth(ctx, in.Token)
+	}
 	if err != nil {
 		if errors.Is(err, models.ErrInvalidToken) ||
 			errors.Is(err, models.ErrUserNotFound) ||
@@ -42,6 +67,103 @@ func (s *serverAPI) Validate(ctx context.Context, in *authv1.ValidateRequest,
 		Valid:  true,
 		UserId: userId,
 		Role:   role,
+		Scopes: scopes,
 	}, nil
 
 }
+
+// GetSigningKeys отдаёт публичные ключи, чтобы клиенты проверяли подпись токенов без вызова Validate
+func (s *serverAPI) GetSigningKeys(ctx context.Context, in *authv1.GetSigningKeysRequest,
+) (*authv1.GetSigningKeysResponse, error) {
+
+	keys, err := s.keys.PublicKeys()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &authv1.GetSigningKeysResponse{Keys: make([]*authv1.SigningKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &authv1.SigningKey{
+			Kid:       key.KeyID,
+			Algorithm: key.Algorithm,
+			PublicKey: key.DER,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetUser(ctx context.Context, in *authv1.GetUserRequest,
+) (*authv1.GetUserResponse, error) {
+
+	if err := validate.Var(in.UserId, "required,uuid"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be a uuid")
+	}
+
+	user, err := s.authService.GetUser(ctx, in.UserId)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &authv1.GetUserResponse{User: toProtoUser(user)}, nil
+}
+
+// BatchGetUsers отдаёт найденных пользователей; отсутствующие id не считаются ошибкой
+func (s *serverAPI) BatchGetUsers(ctx context.Context, in *authv1.BatchGetUsersRequest,
+) (*authv1.BatchGetUsersResponse, error) {
+
+	if len(in.UserIds) > maxBatchUsers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d user_ids per request", maxBatchUsers)
+	}
+	if err := validate.Var(in.UserIds, "dive,uuid"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_ids must be uuids")
+	}
+
+	users, err := s.authService.GetUsers(ctx, in.UserIds)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &authv1.BatchGetUsersResponse{Users: make([]*authv1.User, 0, len(users))}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toProtoUser(user))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) CheckPermission(ctx context.Context, in *authv1.CheckPermissionRequest,
+) (*authv1.CheckPermissionResponse, error) {
+
+	if err := validate.Var(in.UserId, "required,uuid"); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be a uuid")
+	}
+	if in.Permission == "" {
+		return nil, status.Error(codes.InvalidArgument, "permission is required")
+	}
+
+	allowed, role, err := s.authService.CheckPermission(ctx, in.UserId, in.Permission)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &authv1.CheckPermissionResponse{
+		Allowed: allowed,
+		Role:    role,
+	}, nil
+}
+
+// toProtoUser отдаёт только публичные поля: email другим сервисам не передаётся
+func toProtoUser(user *models.User) *authv1.User {
+	return &authv1.User{
+		Id:       user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+	}
+}
@@ -0,0 +1,92 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+)
+
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	var req dto.APIKeyCreateRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err = h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	key, err := h.usersService.CreateAPIKey(r.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidScope) {
+			h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Scope is not allowed for API keys or for your role")
+			return
+		}
+		h.log.Errorw("Failed to create api key", "userID", userID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	keys, err := h.usersService.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		h.log.Errorw("Failed to list api keys", "userID", userID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	keyID := chi.URLParam(r, "keyID")
+	if err = h.validate.Var(keyID, "uuid"); err != nil {
+		h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "API key not found")
+		return
+	}
+
+	if err = h.usersService.RevokeAPIKey(r.Context(), userID, keyID); err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "API key not found")
+			return
+		}
+		h.log.Errorw("Failed to revoke api key", "keyID", keyID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
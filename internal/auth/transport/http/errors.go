@@ -2,7 +2,12 @@ package httphandler
 
 import (
 	"encoding/json"
+	"errors"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type ErrorResponse struct {
@@ -11,11 +16,15 @@ type ErrorResponse struct {
 }
 
 const (
-	ErrCodeValidation  = "VALIDATION_ERROR"
-	ErrCodeNotFound    = "NOT_FOUND"
-	ErrCodeInternal    = "INTERNAL_ERROR"
-	ErrCodeConflict    = "CONFLICT"
-	ErrCodeInvalidJSON = "INVALID_JSON"
+	ErrCodeValidation    = "VALIDATION_ERROR"
+	ErrCodeNotFound      = "NOT_FOUND"
+	ErrCodeInternal      = "INTERNAL_ERROR"
+	ErrCodeConflict      = "CONFLICT"
+	ErrCodeInvalidJSON   = "INVALID_JSON"
+	ErrCodeUnauthorized  = "UNAUTHORIZED"
+	ErrCodeForbidden     = "FORBIDDEN"
+	ErrCodeRateLimited   = "TOO_MANY_REQUESTS"
+	ErrCodeAccountLocked = "ACCOUNT_LOCKED"
 )
 
 func (h *Handler) sendError(w http.ResponseWriter, status int, code, message string) {
@@ -26,3 +35,13 @@ func (h *Handler) sendError(w http.ResponseWriter, status int, code, message str
 		Message: message,
 	})
 }
+
+// sendLockedError отвечает 429 с Retry-After, когда вход временно заблокирован после неудачных попыток
+func (h *Handler) sendLockedError(w http.ResponseWriter, err error) {
+	var lockedErr *models.AccountLockedError
+	if errors.As(err, &lockedErr) {
+		retryAfter := math.Ceil(time.Until(lockedErr.Until).Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(retryAfter), 1)))
+	}
+	h.sendError(w, http.StatusTooManyRequests, ErrCodeAccountLocked, "Too many failed login attempts, try again later")
+}
@@ -0,0 +1,13 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Клиенты кешируют ключи; после ротации новый kid станет виден не позже чем через max-age
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(h.jwks.JWKS())
+}
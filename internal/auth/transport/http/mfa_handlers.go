@@ -0,0 +1,150 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+)
+
+func (h *Handler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	resp, err := h.usersService.LoginMFA(r.Context(), &req, clientInfo(r))
+	if err != nil {
+		if errors.Is(err, models.ErrAccountLocked) {
+			h.sendLockedError(w, err)
+			return
+		}
+		if errors.Is(err, models.ErrInvalidToken) || errors.Is(err, models.ErrTokenExpired) {
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired MFA token")
+			return
+		}
+		if errors.Is(err, models.ErrInvalidMFACode) || errors.Is(err, models.ErrMFANotEnabled) {
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid two-factor code")
+			return
+		}
+		h.log.Errorw("MFA login failed", "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	resp, err := h.usersService.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrMFAAlreadyEnabled) {
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Two-factor authentication is already enabled")
+			return
+		}
+		h.log.Errorw("TOTP enrollment failed", "id", userID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	var req dto.TOTPConfirmRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err = h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	resp, err := h.usersService.ConfirmTOTP(r.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidMFACode):
+			h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid two-factor code")
+		case errors.Is(err, models.ErrMFANotEnabled):
+			h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Two-factor enrollment has not been started")
+		case errors.Is(err, models.ErrMFAAlreadyEnabled):
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Two-factor authentication is already enabled")
+		default:
+			h.log.Errorw("TOTP confirmation failed", "id", userID, "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	var req dto.TOTPDisableRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err = h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	if err = h.usersService.DisableTOTP(r.Context(), userID, &req); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidPassword):
+			h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Password is incorrect")
+		case errors.Is(err, models.ErrInvalidMFACode):
+			h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Invalid two-factor code")
+		case errors.Is(err, models.ErrMFANotEnabled):
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Two-factor authentication is not enabled")
+		default:
+			h.log.Errorw("TOTP disable failed", "id", userID, "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type Authenticator interface {
+	Auth(ctx context.Context, token string) (userId, role, sessionID string, err error)
+}
+
+// AuthMiddleware проверяет access токен и кладёт в контекст id и роль пользователя и сессию токена
+func (h *Handler) AuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authorization header required")
+			return
+		}
+
+		userId, role, sessionID, err := h.authService.Auth(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidToken) || errors.Is(err, models.ErrTokenExpired) {
+				h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired token")
+				return
+			}
+			h.log.Errorw("Token validation failed", "error", err)
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "user_id", userId)
+		ctx = context.WithValue(ctx, "user_role", role)
+		ctx = context.WithValue(ctx, "session_id", sessionID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ParseTrustedProxies разбирает список адресов и подсетей доверенных прокси
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("parse trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// RealIP заменяет RemoteAddr адресом клиента из X-Forwarded-For или X-Real-IP, но только если
+// запрос пришёл от доверенного прокси. Иначе заголовки подделываются, чтобы обойти лимиты входа по IP.
+func (h *Handler) RealIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.trustedProxy(peerAddr(r.RemoteAddr)) {
+			if ip := h.forwardedFor(r); ip != "" {
+				r.RemoteAddr = ip
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedFor идёт по X-Forwarded-For справа налево и возвращает первый адрес не из доверенных прокси:
+// левые элементы клиент может дописать сам
+func (h *Handler) forwardedFor(r *http.Request) string {
+	if header := r.Header.Get("X-Forwarded-For"); header != "" {
+		hops := strings.Split(header, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				return ""
+			}
+			if !h.trustedProxy(addr) {
+				return addr.String()
+			}
+		}
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.String()
+	}
+	return ""
+}
+
+func (h *Handler) trustedProxy(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerAddr(remoteAddr string) netip.Addr {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+	addr, _ := netip.ParseAddr(remoteAddr)
+	return addr
+}
+
+// clientInfo берёт адрес клиента из RemoteAddr (его уже выставил RealIP) и User-Agent
+func clientInfo(r *http.Request) dto.ClientInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return dto.ClientInfo{
+		IP:        ip,
+		UserAgent: r.UserAgent(),
+	}
+}
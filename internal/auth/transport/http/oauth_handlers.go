@@ -0,0 +1,99 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"net/http"
+	"time"
+)
+
+// oauthStateCookie привязывает state к браузеру, начавшему вход
+const oauthStateCookie = "oauth_state"
+
+func (h *Handler) StartOAuth(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	start, err := h.usersService.StartOAuth(r.Context(), provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUnknownProvider):
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "Unknown provider")
+		case errors.Is(err, models.ErrProviderFailed):
+			h.sendError(w, http.StatusBadGateway, ErrCodeInternal, "Provider is unavailable")
+		default:
+			h.log.Errorw("Failed to start oauth", "provider", provider, "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	// Lax: cookie уходит при возврате от провайдера обычной навигацией, но не в сторонних подзапросах
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    start.StateBinding,
+		Path:     "/api/v1/auth/oauth",
+		Expires:  start.ExpiresAt,
+		MaxAge:   int(time.Until(start.ExpiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, start.AuthURL, http.StatusFound)
+}
+
+func (h *Handler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	// Пользователь отказался от входа или провайдер вернул ошибку
+	if providerErr := query.Get("error"); providerErr != "" {
+		h.log.Infow("oauth authorization denied", "provider", provider, "error", providerErr)
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authorization was not granted")
+		return
+	}
+
+	state, code := query.Get("state"), query.Get("code")
+	if state == "" || code == "" {
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "state and code are required")
+		return
+	}
+
+	var stateBinding string
+	if cookie, err := r.Cookie(oauthStateCookie); err == nil {
+		stateBinding = cookie.Value
+	}
+	// state одноразовый, поэтому cookie больше не нужна при любом исходе
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Path:     "/api/v1/auth/oauth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	resp, err := h.usersService.CompleteOAuth(r.Context(), provider, state, stateBinding, code, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUnknownProvider):
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "Unknown provider")
+		case errors.Is(err, models.ErrInvalidToken), errors.Is(err, models.ErrTokenExpired):
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired state")
+		case errors.Is(err, models.ErrEmailNotVerified):
+			h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Provider account has no verified email")
+		case errors.Is(err, models.ErrUserAlreadyExists):
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Account is already linked")
+		case errors.Is(err, models.ErrProviderFailed):
+			h.sendError(w, http.StatusBadGateway, ErrCodeInternal, "Provider is unavailable")
+		default:
+			h.log.Errorw("OAuth login failed", "provider", provider, "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
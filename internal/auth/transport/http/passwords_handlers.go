@@ -0,0 +1,67 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+)
+
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	if err := h.usersService.ForgotPassword(r.Context(), &req, clientInfo(r)); err != nil {
+		if errors.Is(err, models.ErrTooManyRequests) {
+			h.sendError(w, http.StatusTooManyRequests, ErrCodeRateLimited, "Too many requests, try again later")
+			return
+		}
+		h.log.Errorw("Forgot password failed", "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	// Ответ одинаковый для существующих и несуществующих адресов
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	if err := h.usersService.ResetPassword(r.Context(), &req); err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid or expired reset token")
+			return
+		}
+		h.log.Errorw("Reset password failed", "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
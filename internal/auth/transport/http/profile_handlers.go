@@ -0,0 +1,106 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+)
+
+func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := h.usersService.GetProfile(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "User not found")
+			return
+		}
+		h.log.Errorw("Failed to get profile", "id", userID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	var req dto.ProfileUpdateRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err = h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	user, err := h.usersService.UpdateProfile(r.Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrPasswordRequired):
+			h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Current password is required to change email or password")
+		case errors.Is(err, models.ErrInvalidPassword):
+			h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Current password is incorrect")
+		case errors.Is(err, models.ErrUserAlreadyExists):
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Username or email already taken")
+		case errors.Is(err, models.ErrUserNotFound):
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "User not found")
+		default:
+			h.log.Errorw("Failed to update profile", "id", userID, "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	if err := h.usersService.ConfirmEmailChange(r.Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidToken):
+			h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid or expired confirmation token")
+		case errors.Is(err, models.ErrUserAlreadyExists):
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Email already taken")
+		default:
+			h.log.Errorw("Confirm email change failed", "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -6,30 +6,67 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
 	"go.uber.org/zap"
+	"net/netip"
 )
 
 type UsersServiceInterface interface {
 	Register(ctx context.Context, userReq *dto.UserCreateRequest) (string, error)
 	GetUser(ctx context.Context, id string) (*dto.UserResp, error)
-	Login(ctx context.Context, req *dto.LoginRequest) (*dto.LoginResponse, error)
+	Login(ctx context.Context, req *dto.LoginRequest, client dto.ClientInfo) (*dto.LoginResponse, error)
+	LoginMFA(ctx context.Context, req *dto.LoginMFARequest, client dto.ClientInfo) (*dto.LoginResponse, error)
+	StartOAuth(ctx context.Context, provider string) (*dto.OAuthStart, error)
+	CompleteOAuth(ctx context.Context, provider, state, stateBinding, code string, client dto.ClientInfo) (*dto.LoginResponse, error)
+	EnrollTOTP(ctx context.Context, userID string) (*dto.TOTPEnrollResponse, error)
+	ConfirmTOTP(ctx context.Context, userID string, req *dto.TOTPConfirmRequest) (*dto.RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID string, req *dto.TOTPDisableRequest) error
+	Refresh(ctx context.Context, req *dto.RefreshRequest, client dto.ClientInfo) (*dto.LoginResponse, error)
+	Logout(ctx context.Context, accessToken string, req *dto.LogoutRequest) error
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]*dto.SessionResp, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	CreateAPIKey(ctx context.Context, userID string, req *dto.APIKeyCreateRequest) (*dto.APIKeyCreateResponse, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]*dto.APIKeyResp, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest, client dto.ClientInfo) error
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordRequest) error
+	ConfirmEmailChange(ctx context.Context, req *dto.ConfirmEmailChangeRequest) error
+	GetProfile(ctx context.Context, id string) (*dto.UserResp, error)
 	ListUsers(ctx context.Context) ([]*dto.UserResp, error)
 	UpdateUser(ctx context.Context, id string, userReq *dto.UserUpdateRequest) error
+	UpdateProfile(ctx context.Context, id string, req *dto.ProfileUpdateRequest) (*dto.UserResp, error)
 	DeleteUser(ctx context.Context, id string) error
 	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest, client dto.ClientInfo) error
+}
+
+type JWKSProvider interface {
+	JWKS() dto.JWKSResponse
 }
 
 type Handler struct {
-	usersService UsersServiceInterface
-	log          *zap.SugaredLogger
-	validate     *validator.Validate
+	usersService   UsersServiceInterface
+	authService    Authenticator
+	jwks           JWKSProvider
+	log            *zap.SugaredLogger
+	validate       *validator.Validate
+	trustedProxies []netip.Prefix
 }
 
-func NewHandler(usersService UsersServiceInterface, logger *zap.SugaredLogger) *Handler {
+func NewHandler(
+	usersService UsersServiceInterface,
+	authService Authenticator,
+	jwks JWKSProvider,
+	logger *zap.SugaredLogger,
+	trustedProxies []netip.Prefix,
+) *Handler {
 	return &Handler{
-		usersService: usersService,
-		log:          logger,
-		validate:     validator.New(),
+		usersService:   usersService,
+		authService:    authService,
+		jwks:           jwks,
+		log:            logger,
+		validate:       validator.New(),
+		trustedProxies: trustedProxies,
 	}
 }
 
@@ -37,25 +74,56 @@ func (h *Handler) InitRouter() *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
-	router.Use(middleware.RealIP)
+	router.Use(h.RealIP)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
 
+	router.Get("/.well-known/jwks.json", h.JWKS) // GET /.well-known/jwks.json
+
 	router.Route("/api/v1", func(r chi.Router) {
 		r.Route("/auth", func(r chi.Router) {
-			r.Post("/register", h.Register)         // POST /api/v1/auth/register
-			r.Post("/login", h.Login)               // POST /api/v1/auth/login
-			r.Get("/verify/{token}", h.VerifyEmail) // GET /api/v1/auth/verify/{id}
+			r.Post("/register", h.Register)                // POST /api/v1/auth/register
+			r.Post("/login", h.Login)                      // POST /api/v1/auth/login
+			r.Post("/login/mfa", h.LoginMFA)               // POST /api/v1/auth/login/mfa
+			r.Get("/verify/{token}", h.VerifyEmail)        // GET /api/v1/auth/verify/{id}
+			r.Post("/verify/resend", h.ResendVerification) // POST /api/v1/auth/verify/resend
+			r.Post("/refresh", h.Refresh)                  // POST /api/v1/auth/refresh
+			r.Post("/logout", h.Logout)                    // POST /api/v1/auth/logout
 
-			// r.Post("/refresh", h.Refresh)    // POST /api/v1/auth/refresh
+			r.Get("/oauth/{provider}", h.StartOAuth)             // GET /api/v1/auth/oauth/{provider}
+			r.Get("/oauth/{provider}/callback", h.OAuthCallback) // GET /api/v1/auth/oauth/{provider}/callback
 
+			r.Post("/password/forgot", h.ForgotPassword) // POST /api/v1/auth/password/forgot
+			r.Post("/password/reset", h.ResetPassword)   // POST /api/v1/auth/password/reset
+
+			r.Post("/email/confirm", h.ConfirmEmailChange) // POST /api/v1/auth/email/confirm
+
+			r.With(h.AuthMiddleware).Get("/sessions", h.ListSessions)          // GET /api/v1/auth/sessions
+			r.With(h.AuthMiddleware).Delete("/sessions/{id}", h.RevokeSession) // DELETE /api/v1/auth/sessions/{id}
 		})
 		r.Route("/users", func(r chi.Router) {
-			r.Get("/", h.ListUsers) // GET /api/v1/users
+			r.Use(h.AuthMiddleware)
+
+			r.With(authz.Require(authz.UsersManage)).Get("/", h.ListUsers) // GET /api/v1/users
+
+			r.Get("/me", h.GetMe)      // GET /api/v1/users/me
+			r.Patch("/me", h.UpdateMe) // PATCH /api/v1/users/me
+
+			r.Post("/me/mfa/totp", h.EnrollTOTP)          // POST /api/v1/users/me/mfa/totp
+			r.Post("/me/mfa/totp/confirm", h.ConfirmTOTP) // POST /api/v1/users/me/mfa/totp/confirm
+			r.Delete("/me/mfa/totp", h.DisableTOTP)       // DELETE /api/v1/users/me/mfa/totp
+
+			r.Post("/me/api-keys", h.CreateAPIKey)           // POST /api/v1/users/me/api-keys
+			r.Get("/me/api-keys", h.ListAPIKeys)             // GET /api/v1/users/me/api-keys
+			r.Delete("/me/api-keys/{keyID}", h.RevokeAPIKey) // DELETE /api/v1/users/me/api-keys/{keyID}
+
 			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", h.GetUser)       // GET /api/v1/users/{id}
-				r.Put("/", h.UpdateUser)    // PUT /api/v1/users/{id}
-				r.Delete("/", h.DeleteUser) // DELETE /api/v1/users/{id}
+				// Свой профиль доступен любому пользователю, чужой - только с правом users:manage
+				r.Get("/", h.GetUser) // GET /api/v1/users/{id}
+
+				// Полное изменение пользователя, включая роль, доступно только администратору
+				r.With(authz.Require(authz.UsersManage)).Put("/", h.UpdateUser)    // PUT /api/v1/users/{id}
+				r.With(authz.Require(authz.UsersManage)).Delete("/", h.DeleteUser) // DELETE /api/v1/users/{id}
 			})
 		})
 	})
@@ -0,0 +1,54 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+	"net/http"
+)
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	sessions, err := h.usersService.ListSessions(r.Context(), userID, getctx.GetSessionIDFromContext(r.Context()))
+	if err != nil {
+		h.log.Errorw("Failed to list sessions", "userID", userID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, err := getctx.GetUserIDFromContext(r.Context())
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication required")
+		return
+	}
+
+	sessionID := chi.URLParam(r, "id")
+	if err = h.validate.Var(sessionID, "uuid"); err != nil {
+		h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "Session not found")
+		return
+	}
+
+	if err = h.usersService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, models.ErrSessionNotFound) {
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "Session not found")
+			return
+		}
+		h.log.Errorw("Failed to revoke session", "sessionID", sessionID, "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
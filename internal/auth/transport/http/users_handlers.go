@@ -2,10 +2,15 @@ package httphandler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/mSulimenko/dev-blog-platform/internal/auth/dto"
+	"github.com/mSulimenko/dev-blog-platform/internal/auth/models"
+	"github.com/mSulimenko/dev-blog-platform/internal/shared/authz"
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
 )
 
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
@@ -42,6 +47,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.log.Errorw("unable to decode", "error", err, "request", req)
 		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
 	}
 	defer r.Body.Close()
 
@@ -51,8 +57,16 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.usersService.Login(r.Context(), &req)
+	resp, err := h.usersService.Login(r.Context(), &req, clientInfo(r))
 	if err != nil {
+		if errors.Is(err, models.ErrInvalidCredentials) {
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid email or password")
+			return
+		}
+		if errors.Is(err, models.ErrAccountLocked) {
+			h.sendLockedError(w, err)
+			return
+		}
 		h.log.Errorw("Login failed", "error", err)
 		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
 		return
@@ -62,9 +76,81 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	resp, err := h.usersService.Refresh(r.Context(), &req, clientInfo(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidToken),
+			errors.Is(err, models.ErrTokenExpired),
+			errors.Is(err, models.ErrTokenReused):
+			h.log.Infow("Refresh rejected", "error", err)
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired refresh token")
+		default:
+			h.log.Errorw("Refresh failed", "error", err)
+			h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Authorization header required")
+		return
+	}
+
+	// Тело необязательно: без него отзывается только предъявленный access токен
+	var req dto.LogoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.log.Errorw("unable to decode", "error", err)
+			h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+			return
+		}
+	}
+	defer r.Body.Close()
+
+	if err := h.usersService.Logout(r.Context(), token, &req); err != nil {
+		if errors.Is(err, models.ErrInvalidToken) || errors.Is(err, models.ErrTokenExpired) {
+			h.sendError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "Invalid or expired token")
+			return
+		}
+		h.log.Errorw("Logout failed", "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
+	userID, _ := getctx.GetUserIDFromContext(r.Context())
+	role, _ := getctx.GetUserRoleFromContext(r.Context())
+	if id != userID && !authz.Can(role, authz.UsersManage) {
+		h.sendError(w, http.StatusForbidden, ErrCodeForbidden, "Insufficient permissions")
+		return
+	}
+
 	user, err := h.usersService.GetUser(r.Context(), id)
 	if err != nil {
 		h.log.Errorw("Failed to get user", "id", id, "error", err)
@@ -106,6 +192,14 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	err := h.usersService.UpdateUser(r.Context(), id, &req)
 	if err != nil {
+		if errors.Is(err, models.ErrUserAlreadyExists) {
+			h.sendError(w, http.StatusConflict, ErrCodeConflict, "Username or email already taken")
+			return
+		}
+		if errors.Is(err, models.ErrUserNotFound) {
+			h.sendError(w, http.StatusNotFound, ErrCodeNotFound, "User not found")
+			return
+		}
 		h.log.Errorw("Failed to update user", "id", id, "error", err)
 		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Internal server error")
 		return
@@ -136,7 +230,7 @@ func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 
 	err := h.usersService.VerifyEmail(r.Context(), token)
 	if err != nil {
-		h.log.Errorw("failed to verify email", "token", token, "error", err)
+		h.log.Errorw("failed to verify email", "error", err)
 		h.sendVerificationHTML(w, false, "Invalid or expired verification token")
 		return
 	}
@@ -144,6 +238,35 @@ func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	h.sendVerificationHTML(w, true, "Email successfully verified!")
 }
 
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.log.Errorw("unable to decode", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeInvalidJSON, "Invalid JSON format")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.validate.Struct(req); err != nil {
+		h.log.Errorw("Validation failed", "error", err)
+		h.sendError(w, http.StatusBadRequest, ErrCodeValidation, "Invalid input data")
+		return
+	}
+
+	if err := h.usersService.ResendVerification(r.Context(), &req, clientInfo(r)); err != nil {
+		if errors.Is(err, models.ErrTooManyRequests) {
+			h.sendError(w, http.StatusTooManyRequests, ErrCodeRateLimited, "Too many requests, try again later")
+			return
+		}
+		h.log.Errorw("Resend verification failed", "error", err)
+		h.sendError(w, http.StatusInternalServerError, ErrCodeInternal, "Server error")
+		return
+	}
+
+	// Ответ одинаковый для существующих и несуществующих адресов
+	w.WriteHeader(http.StatusAccepted)
+}
+
 func (h *Handler) sendVerificationHTML(w http.ResponseWriter, success bool, message string) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 
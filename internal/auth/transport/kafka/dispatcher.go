@@ -7,10 +7,14 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/mSulimenko/dev-blog-platform/internal/shared/events/dto"
 	"go.uber.org/zap"
+	"time"
 )
 
 const (
-	userRegisteredTopic = "user-registered"
+	userRegisteredTopic         = "user-registered"
+	passwordResetRequestedTopic = "password-reset-requested"
+	emailChangeRequestedTopic   = "email-change-requested"
+	accountLockedTopic          = "account-locked"
 )
 
 type Dispatcher struct {
@@ -43,25 +47,61 @@ func (d *Dispatcher) UserRegistered(ctx context.Context, email, token, username
 		Username: username,
 	}
 
+	return d.send(userRegisteredTopic, email, event)
+}
+
+func (d *Dispatcher) PasswordResetRequested(ctx context.Context, email, token, username string) error {
+	event := dto.PasswordResetRequestedEvent{
+		Email:    email,
+		Token:    token,
+		Username: username,
+	}
+
+	return d.send(passwordResetRequestedTopic, email, event)
+}
+
+func (d *Dispatcher) EmailChangeRequested(ctx context.Context, oldEmail, newEmail, token, username string) error {
+	event := dto.EmailChangeRequestedEvent{
+		OldEmail: oldEmail,
+		NewEmail: newEmail,
+		Token:    token,
+		Username: username,
+	}
+
+	return d.send(emailChangeRequestedTopic, oldEmail, event)
+}
+
+func (d *Dispatcher) AccountLocked(ctx context.Context, email, username string, lockedUntil time.Time, ip string) error {
+	event := dto.AccountLockedEvent{
+		Email:       email,
+		Username:    username,
+		LockedUntil: lockedUntil,
+		IP:          ip,
+	}
+
+	return d.send(accountLockedTopic, email, event)
+}
+
+func (d *Dispatcher) send(topic, key string, event any) error {
 	jsonEvent, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshall event: %w", err)
 	}
 
 	msg := &sarama.ProducerMessage{
-		Topic: userRegisteredTopic,
-		Key:   sarama.StringEncoder(email),
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
 		Value: sarama.ByteEncoder(jsonEvent),
 	}
 
 	partition, offset, err := d.producer.SendMessage(msg)
 	if err != nil {
-		d.log.Errorw("failed to send user_registered event",
-			"error", err, "email", email)
+		d.log.Errorw("failed to send event",
+			"error", err, "topic", topic, "key", key)
 		return err
 	}
-	d.log.Infow("user_registered event sent",
-		"email", email, "partition", partition, "offset", offset)
+	d.log.Infow("event sent",
+		"topic", topic, "key", key, "partition", partition, "offset", offset)
 	return nil
 }
 
@@ -21,6 +21,7 @@ type EmailConfig struct {
 	SMTPPort     string `yaml:"smtp_port"`
 	FromEmail    string `yaml:"from_email"`
 	FromPassword string `yaml:"from_password"`
+	AppURL       string `yaml:"app_url" env:"APP_URL" env-default:"http://localhost:3000"`
 }
 
 type KafkaConfig struct {
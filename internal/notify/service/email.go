@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"go.uber.org/zap"
+	"html"
 	"net/smtp"
+	"net/url"
+	"time"
 )
 
 type EmailService struct {
@@ -12,6 +15,7 @@ type EmailService struct {
 	smtpPort     string
 	fromEmail    string
 	fromPassword string
+	appURL       string
 	log          *zap.SugaredLogger
 }
 
@@ -20,6 +24,7 @@ type EmailConfig struct {
 	SMTPPort     string
 	FromEmail    string
 	FromPassword string
+	AppURL       string
 }
 
 func NewEmailService(cfg EmailConfig, log *zap.SugaredLogger) *EmailService {
@@ -28,6 +33,7 @@ func NewEmailService(cfg EmailConfig, log *zap.SugaredLogger) *EmailService {
 		smtpPort:     cfg.SMTPPort,
 		fromEmail:    cfg.FromEmail,
 		fromPassword: cfg.FromPassword,
+		appURL:       cfg.AppURL,
 		log:          log,
 	}
 }
@@ -72,6 +78,160 @@ func (s *EmailService) buildVerificationEmail(username, verificationURL string)
 </html>`, username, verificationURL, verificationURL)
 }
 
+func (s *EmailService) SendPasswordResetEmail(ctx context.Context, email, username, token string) error {
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", s.appURL, url.QueryEscape(token))
+
+	subject := "Reset Your Password"
+	body := s.buildPasswordResetEmail(username, resetURL)
+
+	return s.sendEmail(email, subject, body)
+}
+
+func (s *EmailService) buildPasswordResetEmail(username, resetURL string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .button { background-color: #007bff; color: white; padding: 12px 24px; 
+                  text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h2>Reset Your Password</h2>
+        <p>Hello %s,</p>
+        <p>We received a request to reset your password. Click the button below to choose a new one:</p>
+        <p>
+            <a href="%s" class="button">Reset Password</a>
+        </p>
+        <p>Or copy and paste this link in your browser:</p>
+        <p>%s</p>
+        <p>The link is valid for a limited time and can be used only once.</p>
+        <div class="footer">
+            <p>If you didn't request a password reset, please ignore this email. Your password will not change.</p>
+        </div>
+    </div>
+</body>
+</html>`, html.EscapeString(username), resetURL, resetURL)
+}
+
+func (s *EmailService) SendEmailChangeConfirmation(ctx context.Context, email, username, token string) error {
+	confirmURL := fmt.Sprintf("%s/confirm-email?token=%s", s.appURL, url.QueryEscape(token))
+
+	subject := "Confirm Your New Email Address"
+	body := s.buildEmailChangeConfirmation(username, confirmURL)
+
+	return s.sendEmail(email, subject, body)
+}
+
+func (s *EmailService) buildEmailChangeConfirmation(username, confirmURL string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .button { background-color: #007bff; color: white; padding: 12px 24px; 
+                  text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h2>Confirm Your New Email Address</h2>
+        <p>Hello %s,</p>
+        <p>We received a request to use this address for your account. Click the button below to confirm the change:</p>
+        <p>
+            <a href="%s" class="button">Confirm Email</a>
+        </p>
+        <p>Or copy and paste this link in your browser:</p>
+        <p>%s</p>
+        <p>Until you confirm, your account keeps using the previous address.</p>
+        <div class="footer">
+            <p>If you didn't request this change, please ignore this email.</p>
+        </div>
+    </div>
+</body>
+</html>`, html.EscapeString(username), confirmURL, confirmURL)
+}
+
+func (s *EmailService) SendEmailChangeAlert(ctx context.Context, email, username, newEmail string) error {
+	subject := "Email Change Requested"
+	body := s.buildEmailChangeAlert(username, newEmail)
+
+	return s.sendEmail(email, subject, body)
+}
+
+func (s *EmailService) buildEmailChangeAlert(username, newEmail string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .footer { margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h2>Email Change Requested</h2>
+        <p>Hello %s,</p>
+        <p>Someone asked to change the email address of your account to <strong>%s</strong>.</p>
+        <p>The change takes effect only after it is confirmed from the new address.</p>
+        <div class="footer">
+            <p>If this wasn't you, change your password right away.</p>
+        </div>
+    </div>
+</body>
+</html>`, html.EscapeString(username), html.EscapeString(newEmail))
+}
+
+func (s *EmailService) SendAccountLockedEmail(ctx context.Context, email, username string, lockedUntil time.Time, ip string) error {
+	resetURL := fmt.Sprintf("%s/forgot-password", s.appURL)
+
+	subject := "Your Account Has Been Temporarily Locked"
+	body := s.buildAccountLockedEmail(username, lockedUntil, ip, resetURL)
+
+	return s.sendEmail(email, subject, body)
+}
+
+func (s *EmailService) buildAccountLockedEmail(username string, lockedUntil time.Time, ip, resetURL string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .button { background-color: #007bff; color: white; padding: 12px 24px; 
+                  text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { margin-top: 20px; font-size: 12px; color: #666; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <h2>Your Account Has Been Temporarily Locked</h2>
+        <p>Hello %s,</p>
+        <p>We noticed several failed sign-in attempts to your account (last one from IP %s),
+           so signing in is blocked until %s.</p>
+        <p>If it was you, just wait and try again. If not, we recommend resetting your password:</p>
+        <p>
+            <a href="%s" class="button">Reset Password</a>
+        </p>
+        <div class="footer">
+            <p>No one has signed in to your account as a result of these attempts.</p>
+        </div>
+    </div>
+</body>
+</html>`, html.EscapeString(username), html.EscapeString(ip), lockedUntil.UTC().Format("2006-01-02 15:04 MST"), resetURL)
+}
+
 func (s *EmailService) sendEmail(to, subject, body string) error {
 	auth := smtp.PlainAuth("", s.fromEmail, s.fromPassword, s.smtpHost)
 
@@ -95,6 +255,6 @@ func (s *EmailService) sendEmail(to, subject, body string) error {
 		return err
 	}
 
-	s.log.Infof("Verification email sent to %s", to)
+	s.log.Infof("Email %q sent to %s", subject, to)
 	return nil
 }
@@ -8,12 +8,18 @@ import (
 	"github.com/mSulimenko/dev-blog-platform/internal/notify/service"
 	"github.com/mSulimenko/dev-blog-platform/internal/shared/events/dto"
 	"go.uber.org/zap"
+	"sync"
 )
 
 const (
-	userRegisteredTopic = "user-registered"
+	userRegisteredTopic         = "user-registered"
+	passwordResetRequestedTopic = "password-reset-requested"
+	emailChangeRequestedTopic   = "email-change-requested"
+	accountLockedTopic          = "account-locked"
 )
 
+type messageHandler func(msg *sarama.ConsumerMessage) error
+
 type Consumer struct {
 	consumer     sarama.Consumer
 	log          *zap.SugaredLogger
@@ -39,38 +45,60 @@ func NewConsumer(brokers []string, emailService *service.EmailService, log *zap.
 func (c *Consumer) Start() {
 	c.log.Info("Starting Kafka consumer with Sarama...")
 
-	partitionConsumer, err := c.consumer.ConsumePartition(userRegisteredTopic, 0, sarama.OffsetNewest)
-	if err != nil {
-		c.log.Errorf("failed to start consumer: %v", err)
-		return
+	handlers := map[string]messageHandler{
+		userRegisteredTopic:         c.handleUserRegistered,
+		passwordResetRequestedTopic: c.handlePasswordResetRequested,
+		emailChangeRequestedTopic:   c.handleEmailChangeRequested,
+		accountLockedTopic:          c.handleAccountLocked,
+	}
+
+	var wg sync.WaitGroup
+	for topic, handler := range handlers {
+		partitionConsumer, err := c.consumer.ConsumePartition(topic, 0, sarama.OffsetNewest)
+		if err != nil {
+			c.log.Errorf("failed to start consumer for topic %s: %v", topic, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer partitionConsumer.Close()
+
+			c.consume(topic, partitionConsumer, handler)
+		}()
 	}
-	defer partitionConsumer.Close()
 
-	c.log.Info("Started listening topic ", userRegisteredTopic)
+	wg.Wait()
+}
+
+func (c *Consumer) consume(topic string, partitionConsumer sarama.PartitionConsumer, handler messageHandler) {
+	c.log.Info("Started listening topic ", topic)
 
 	for {
 		select {
-		case msg := <-partitionConsumer.Messages():
-			c.handleMessage(msg)
-		case err = <-partitionConsumer.Errors():
+		case msg, ok := <-partitionConsumer.Messages():
+			if !ok {
+				return
+			}
+			if err := handler(msg); err != nil {
+				c.log.Errorf("Handle event error: %v", err)
+			}
+		case err, ok := <-partitionConsumer.Errors():
+			if !ok {
+				return
+			}
 			c.log.Errorf("Kafka consumer error: %v", err)
 		}
 	}
 }
 
-func (c *Consumer) handleMessage(msg *sarama.ConsumerMessage) {
+func (c *Consumer) handleUserRegistered(msg *sarama.ConsumerMessage) error {
 	var event dto.UserRegisteredEvent
 	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		c.log.Errorf("Parse event error: %v", err)
-		return
+		return fmt.Errorf("parse event: %w", err)
 	}
 
-	if err := c.handleUserRegistered(event); err != nil {
-		c.log.Errorf("Handle event error: %v", err)
-	}
-}
-
-func (c *Consumer) handleUserRegistered(event dto.UserRegisteredEvent) error {
 	c.log.Infof("Processing user registration: %s", event.Email)
 
 	err := c.emailService.SendVerificationEmail(context.Background(), event.Email, event.Username, event.Token)
@@ -83,6 +111,66 @@ func (c *Consumer) handleUserRegistered(event dto.UserRegisteredEvent) error {
 	return nil
 }
 
+func (c *Consumer) handlePasswordResetRequested(msg *sarama.ConsumerMessage) error {
+	var event dto.PasswordResetRequestedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("parse event: %w", err)
+	}
+
+	c.log.Infof("Processing password reset: %s", event.Email)
+
+	err := c.emailService.SendPasswordResetEmail(context.Background(), event.Email, event.Username, event.Token)
+	if err != nil {
+		c.log.Errorf("Failed to send password reset email to %s: %v", event.Email, err)
+		return err
+	}
+
+	c.log.Infof("Password reset email sent to %s", event.Email)
+	return nil
+}
+
+func (c *Consumer) handleEmailChangeRequested(msg *sarama.ConsumerMessage) error {
+	var event dto.EmailChangeRequestedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("parse event: %w", err)
+	}
+
+	c.log.Infof("Processing email change: %s -> %s", event.OldEmail, event.NewEmail)
+
+	err := c.emailService.SendEmailChangeConfirmation(context.Background(), event.NewEmail, event.Username, event.Token)
+	if err != nil {
+		c.log.Errorf("Failed to send email change confirmation to %s: %v", event.NewEmail, err)
+		return err
+	}
+
+	// Уведомление на старый адрес не критично: подтверждение уже отправлено
+	err = c.emailService.SendEmailChangeAlert(context.Background(), event.OldEmail, event.Username, event.NewEmail)
+	if err != nil {
+		c.log.Errorf("Failed to send email change alert to %s: %v", event.OldEmail, err)
+	}
+
+	c.log.Infof("Email change confirmation sent to %s", event.NewEmail)
+	return nil
+}
+
+func (c *Consumer) handleAccountLocked(msg *sarama.ConsumerMessage) error {
+	var event dto.AccountLockedEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return fmt.Errorf("parse event: %w", err)
+	}
+
+	c.log.Infof("Processing account lock: %s", event.Email)
+
+	err := c.emailService.SendAccountLockedEmail(context.Background(), event.Email, event.Username, event.LockedUntil, event.IP)
+	if err != nil {
+		c.log.Errorf("Failed to send account locked email to %s: %v", event.Email, err)
+		return err
+	}
+
+	c.log.Infof("Account locked email sent to %s", event.Email)
+	return nil
+}
+
 func (c *Consumer) Close() error {
 	return c.consumer.Close()
 }
@@ -0,0 +1,70 @@
+package authz
+
+import "slices"
+
+type Permission string
+
+// Права без суффикса :any относятся к собственным статьям и комментариям
+const (
+	ArticlesCreate    Permission = "articles:create"
+	ArticlesUpdate    Permission = "articles:update"
+	ArticlesDelete    Permission = "articles:delete"
+	ArticlesRestore   Permission = "articles:restore"
+	ArticlesUpdateAny Permission = "articles:update:any"
+	ArticlesDeleteAny Permission = "articles:delete:any"
+	CommentsCreate    Permission = "comments:create"
+	CommentsDelete    Permission = "comments:delete"
+	CommentsDeleteAny Permission = "comments:delete:any"
+	UsersManage       Permission = "users:manage"
+)
+
+const (
+	RoleUnverified = "unverified"
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+)
+
+// rolePermissions - права ролей; неподтверждённые пользователи могут только читать
+var rolePermissions = map[string][]Permission{
+	RoleUnverified: {},
+	RoleUser: {
+		ArticlesCreate,
+		ArticlesUpdate,
+		ArticlesDelete,
+		ArticlesRestore,
+		CommentsCreate,
+		CommentsDelete,
+	},
+	RoleAdmin: {
+		ArticlesCreate,
+		ArticlesUpdate,
+		ArticlesDelete,
+		ArticlesRestore,
+		ArticlesUpdateAny,
+		ArticlesDeleteAny,
+		CommentsCreate,
+		CommentsDelete,
+		CommentsDeleteAny,
+		UsersManage,
+	},
+}
+
+// Can сообщает, есть ли у роли право; неизвестная роль не имеет прав
+func Can(role string, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// APIKeyScopes - права, которые можно выдать API ключу. Административные права ключам не выдаются.
+var APIKeyScopes = []Permission{
+	ArticlesCreate,
+	ArticlesUpdate,
+	ArticlesDelete,
+	ArticlesRestore,
+	CommentsCreate,
+	CommentsDelete,
+}
+
+// Scoped сообщает, разрешено ли право набором scopes; nil означает вход без ограничений (не по API ключу)
+func Scoped(scopes []string, permission Permission) bool {
+	return scopes == nil || slices.Contains(scopes, string(permission))
+}
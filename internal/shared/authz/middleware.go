@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"encoding/json"
+	"net/http"
+
+	getctx "github.com/mSulimenko/dev-blog-platform/internal/shared/context"
+)
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Require пропускает запрос, только если у роли пользователя есть все перечисленные права,
+// а при входе по API ключу они есть и в scopes ключа.
+// Роль берётся из контекста, поэтому middleware ставится после аутентификации.
+func Require(permissions ...Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, err := getctx.GetUserRoleFromContext(r.Context())
+			if err != nil {
+				sendError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Authentication required")
+				return
+			}
+
+			scopes := getctx.GetUserScopesFromContext(r.Context())
+			for _, permission := range permissions {
+				if !Can(role, permission) || !Scoped(scopes, permission) {
+					sendError(w, http.StatusForbidden, "FORBIDDEN", "Insufficient permissions")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func sendError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{
+		Code:    code,
+		Message: message,
+	})
+}
@@ -20,3 +20,15 @@ func GetUserRoleFromContext(ctx context.Context) (string, error) {
 	}
 	return role, nil
 }
+
+// GetSessionIDFromContext возвращает сессию access токена; пустая строка у токенов без сессии
+func GetSessionIDFromContext(ctx context.Context) string {
+	sessionID, _ := ctx.Value("session_id").(string)
+	return sessionID
+}
+
+// GetUserScopesFromContext возвращает scopes API ключа; nil, если пользователь вошёл по токену
+func GetUserScopesFromContext(ctx context.Context) []string {
+	scopes, _ := ctx.Value("user_scopes").([]string)
+	return scopes
+}
@@ -1,7 +1,29 @@
 package dto
 
+import "time"
+
 type UserRegisteredEvent struct {
 	Email    string `json:"email"`
 	Token    string `json:"token"`
 	Username string `json:"username"`
 }
+
+type PasswordResetRequestedEvent struct {
+	Email    string `json:"email"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+}
+
+type EmailChangeRequestedEvent struct {
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+}
+
+type AccountLockedEvent struct {
+	Email       string    `json:"email"`
+	Username    string    `json:"username"`
+	LockedUntil time.Time `json:"locked_until"`
+	IP          string    `json:"ip"`
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS article_revisions
+(
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID         NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    revision   INT          NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    content    TEXT         NOT NULL,
+    editor_id  UUID         NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (article_id, revision)
+);
+
+-- Текущее состояние существующих статей становится их первой ревизией
+INSERT INTO article_revisions (article_id, revision, title, content, editor_id, created_at)
+SELECT id, 1, title, content, author_id, COALESCE(updated_at, NOW())
+FROM articles;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS article_revisions;
+-- +goose StatementEnd
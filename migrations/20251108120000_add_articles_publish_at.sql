@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_articles_scheduled ON articles(publish_at)
+    WHERE status = 'draft' AND publish_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_scheduled;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS publish_at;
+-- +goose StatementEnd
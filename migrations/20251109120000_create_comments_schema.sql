@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS comments
+(
+    id         UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_id UUID        NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    parent_id  UUID REFERENCES comments (id) ON DELETE CASCADE,
+    author_id  UUID        NOT NULL,
+    content    TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_article_top_level ON comments(article_id, created_at, id)
+    WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_comments_article_top_level;
+DROP INDEX IF EXISTS idx_comments_parent_id;
+
+DROP TABLE IF EXISTS comments;
+-- +goose StatementEnd
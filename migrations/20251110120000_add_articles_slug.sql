@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+
+-- Транслитерация выполняется в приложении, поэтому уже существующие статьи получают slug из id;
+-- человекочитаемый slug появится при следующем изменении заголовка
+UPDATE articles SET slug = id::text WHERE slug IS NULL;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_slug ON articles(slug);
+
+-- Прежние slug статей, по которым отдаётся редирект на актуальный
+CREATE TABLE IF NOT EXISTS article_slugs
+(
+    slug       VARCHAR(100) PRIMARY KEY,
+    article_id UUID        NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs(article_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_article_slugs_article_id;
+DROP TABLE IF EXISTS article_slugs;
+
+DROP INDEX IF EXISTS idx_articles_slug;
+ALTER TABLE articles DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
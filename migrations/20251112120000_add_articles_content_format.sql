@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS content_format VARCHAR(20) NOT NULL DEFAULT 'plain'
+        CHECK (content_format IN ('markdown', 'html', 'plain')),
+    ADD COLUMN IF NOT EXISTS content_html TEXT NOT NULL DEFAULT '';
+
+-- Существующие статьи хранились как простой текст; content_html строится так же, как markup.Plain
+UPDATE articles
+SET content_html = '<p>' || replace(
+        replace(
+                regexp_replace(
+                        replace(replace(replace(
+                                btrim(replace(content, E'\r\n', E'\n'), E' \n'),
+                                '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+                        E'\n{2,}', '</p><p>', 'g'),
+                E'\n', '<br>'),
+        '</p><p>', E'</p>\n<p>') || '</p>'
+WHERE content_html = '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS content_html,
+    DROP COLUMN IF EXISTS content_format;
+-- +goose StatementEnd
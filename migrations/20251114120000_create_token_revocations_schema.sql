@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Отозванные access токены хранятся до истечения их exp
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        VARCHAR(64) PRIMARY KEY,
+    user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Выход из всех сессий: недействительны все токены пользователя, выпущенные не позже revoked_before
+CREATE TABLE IF NOT EXISTS user_revocations
+(
+    user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    revoked_before TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_revocations;
+
+DROP INDEX IF EXISTS idx_revoked_tokens_expires_at;
+DROP TABLE IF EXISTS revoked_tokens;
+-- +goose StatementEnd
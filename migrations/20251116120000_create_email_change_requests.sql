@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS email_change_requests
+(
+    id           UUID PRIMARY KEY      DEFAULT uuid_generate_v4(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    new_email    VARCHAR(100) NOT NULL,
+    token_hash   VARCHAR(64)  NOT NULL UNIQUE,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_change_requests_user_id ON email_change_requests(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_email_change_requests_user_id;
+
+DROP TABLE IF EXISTS email_change_requests;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS verification_expires_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS verification_sent_at    TIMESTAMPTZ;
+
+-- Токены хранятся как sha256; уже отправленные ссылки продолжают работать ещё сутки
+UPDATE users
+SET verification_token      = encode(sha256(verification_token::bytea), 'hex'),
+    verification_expires_at = NOW() + INTERVAL '24 hours',
+    verification_sent_at    = created_at
+WHERE verification_token IS NOT NULL
+  AND verification_token <> '';
+
+CREATE INDEX IF NOT EXISTS idx_users_verification_token ON users(verification_token);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Хеши обратно в токены не превращаются: неподтверждённым пользователям нужно запросить письмо заново
+DROP INDEX IF EXISTS idx_users_verification_token;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS verification_sent_at,
+    DROP COLUMN IF EXISTS verification_expires_at;
+-- +goose StatementEnd
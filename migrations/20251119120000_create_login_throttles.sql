@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS login_throttles
+(
+    scope           VARCHAR(10)  NOT NULL,
+    subject         VARCHAR(100) NOT NULL,
+    failures        INTEGER      NOT NULL DEFAULT 0,
+    locked_until    TIMESTAMPTZ,
+    last_failure_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_last_failure_at ON login_throttles(last_failure_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_login_throttles_last_failure_at;
+
+DROP TABLE IF EXISTS login_throttles;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Сессия - одно устройство/вход; её id совпадает с family_id refresh токенов
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id           UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    user_id      UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_agent   TEXT        NOT NULL DEFAULT '',
+    ip           VARCHAR(45) NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Действующие семейства refresh токенов становятся сессиями без сведений об устройстве
+INSERT INTO sessions (id, user_id, created_at, last_used_at)
+SELECT family_id, user_id, MIN(created_at), MAX(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_sessions_user_id;
+
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Незавершённые входы через внешних провайдеров: state хранится хешем, code_verifier нужен для PKCE
+CREATE TABLE IF NOT EXISTS oauth_states
+(
+    state_hash    VARCHAR(64) PRIMARY KEY,
+    provider      VARCHAR(50)  NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at    TIMESTAMPTZ  NOT NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires_at ON oauth_states(expires_at);
+
+-- Привязка аккаунта провайдера (provider + subject) к пользователю
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    provider   VARCHAR(50)  NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    user_id    UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_user_identities_user_id;
+DROP TABLE IF EXISTS user_identities;
+
+DROP INDEX IF EXISTS idx_oauth_states_expires_at;
+DROP TABLE IF EXISTS oauth_states;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Персональные API ключи: хранится только хеш, prefix позволяет узнать ключ в списке и в логах
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           UUID PRIMARY KEY      DEFAULT uuid_generate_v4(),
+    user_id      UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    prefix       VARCHAR(20)  NOT NULL UNIQUE,
+    key_hash     VARCHAR(64)  NOT NULL UNIQUE,
+    scopes       TEXT[]       NOT NULL DEFAULT '{}',
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_user_id;
+
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd